@@ -0,0 +1,52 @@
+package quote
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Args is a list of command-line arguments rendered as a single command line quoted with Q.
+//
+// It implements fmt.Formatter:
+//
+//	%s	the command line as returned by Join
+//	%q	the command line as a double-quoted Go string
+//	%v	every argument quoted with Q in brackets, like ['echo' 'a b']
+//
+// It also implements slog.LogValuer, logging the command line as a string.
+type Args struct {
+	Q    Quoting
+	Args []string
+}
+
+// String returns the command line as returned by Join.
+func (a Args) String() string {
+	return Join(a.Q, a.Args)
+}
+
+// Format implements fmt.Formatter.
+func (a Args) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 's', 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), a.String())
+	case 'v':
+		var buf strings.Builder
+		buf.WriteByte('[')
+		for i, arg := range a.Args {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString(a.Q.Quote(arg))
+		}
+		buf.WriteByte(']')
+		fmt.Fprintf(f, fmt.FormatString(f, 's'), buf.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(quote.Args=%s)", verb, a.String())
+	}
+}
+
+// LogValue implements slog.LogValuer.
+func (a Args) LogValue() slog.Value {
+	return slog.StringValue(a.String())
+}
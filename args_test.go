@@ -0,0 +1,59 @@
+package quote_test
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleArgs() {
+	args := quote.Args{Q: unix.SingleQuote, Args: []string{"echo", "it's", ""}}
+	fmt.Printf("%s\n", args)
+	fmt.Printf("%v\n", args)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("spawning", "cmd", args)
+	// Output:
+	// echo 'it'"'"'s' ''
+	// ['echo' 'it'"'"'s' '']
+	// level=INFO msg=spawning cmd="echo 'it'\"'\"'s' ''"
+}
+
+func TestArgs_Format(t *testing.T) {
+	args := quote.Args{Q: windows.Argv, Args: []string{"a", "b c"}}
+	tests := []struct {
+		Format, Output string
+	}{
+		{Format: "%s", Output: `a "b c"`},
+		{Format: "%10s", Output: `   a "b c"`},
+		{Format: "%-10s|", Output: `a "b c"   |`},
+		{Format: "%q", Output: `"a \"b c\""`},
+		{Format: "%v", Output: `["a" "b c"]`},
+		{Format: "%d", Output: `%!d(quote.Args=a "b c")`},
+	}
+	for _, td := range tests {
+		t.Run(td.Format, func(t *testing.T) {
+			testutil.TestDiff(t, "Sprintf()", td.Output, fmt.Sprintf(td.Format, args))
+		})
+	}
+}
+
+func TestArgs_LogValue(t *testing.T) {
+	v := quote.Args{Q: unix.DoubleQuote, Args: []string{"echo", "$HOME"}}.LogValue()
+	if v.Kind() != slog.KindString {
+		t.Fatalf("LogValue().Kind() = %v; want %v", v.Kind(), slog.KindString)
+	}
+	testutil.TestDiff(t, "LogValue()", `echo "\$HOME"`, v.String())
+}
@@ -0,0 +1,101 @@
+package quote
+
+// Limit describes the maximum size of a command line.
+type Limit struct {
+	Length int  // maximum length of the command line, 0 if unlimited
+	Args   int  // maximum number of arguments, including fixed ones, 0 if unlimited
+	UTF16  bool // length is counted in UTF-16 code units instead of bytes
+}
+
+// Common limits.
+var (
+	// UnixLimit is the limit used by xargs by default, 128 KiB,
+	// which is well below ARG_MAX on modern Unix-based systems
+	// leaving room for the environment.
+	UnixLimit = Limit{Length: 128 * 1024}
+
+	// WindowsLimit is the limit of a command line passed to the CreateProcess function,
+	// 32767 characters including the terminating NUL character.
+	WindowsLimit = Limit{Length: 32767 - 1, UTF16: true}
+
+	// CmdLimit is the limit of a command line run by the Windows command interpreter (cmd.exe).
+	CmdLimit = Limit{Length: 8191, UTF16: true}
+)
+
+// Batch splits variable into batches of arguments, like xargs does,
+// so that every invocation of the fixed arguments followed by a batch,
+// quoted with q as by Join, fits limit. It returns the arguments of every invocation.
+//
+// For Windows programs q is usually windows.Argv or, when the command line goes through cmd.exe,
+// quote.Chain(windows.Argv, windows.Cmd). On Unix-based systems arguments are passed
+// to programs unquoted, separated by NUL bytes, so a nil q measures arguments as they are.
+//
+// Batch returns no invocations if variable is empty or if there are limit.Args fixed arguments
+// or more, as no invocation can fit then.
+// An argument too long to fit limit along with the fixed arguments
+// is placed in an invocation by itself, which still exceeds limit.
+func Batch(fixed, variable []string, q Quoting, limit Limit) [][]string {
+	if !limit.fits(0, len(fixed)+1) {
+		return nil
+	}
+	fixedLen := 0
+	for i, arg := range fixed {
+		fixedLen += limit.argLength(q, arg, i)
+	}
+	var (
+		batches [][]string
+		batch   []string
+		length  int
+	)
+	for _, arg := range variable {
+		if batch != nil {
+			n := limit.argLength(q, arg, len(batch))
+			if limit.fits(length+n, len(batch)+1) {
+				batch = append(batch, arg)
+				length += n
+				continue
+			}
+			batches = append(batches, batch)
+		}
+		batch = append(append(make([]string, 0, len(fixed)+1), fixed...), arg)
+		length = fixedLen + limit.argLength(q, arg, len(fixed))
+	}
+	if batch != nil {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// argLength returns the length of the i-th argument of a command line,
+// including the preceding separator.
+func (l Limit) argLength(q Quoting, arg string, i int) int {
+	if q != nil && mustQuote(q, arg, i, QuoteNeeded) {
+		arg = q.Quote(arg)
+	}
+	n := len(arg)
+	if l.UTF16 {
+		n = utf16Len(arg)
+	}
+	if i > 0 {
+		n++
+	}
+	return n
+}
+
+func (l Limit) fits(length, args int) bool {
+	return (l.Length <= 0 || length <= l.Length) && (l.Args <= 0 || args <= l.Args)
+}
+
+// utf16Len returns the number of UTF-16 code units encoding s,
+// counting every invalid UTF-8 byte as one code unit.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,124 @@
+package quote_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleBatch() {
+	files := []string{"a.txt", "b c.txt", "d.txt", "e.txt"}
+	for _, args := range quote.Batch([]string{"del", "/q"}, files, windows.Argv, quote.Limit{Length: 20}) {
+		fmt.Println(quote.Join(windows.Argv, args))
+	}
+	// Output:
+	// del /q a.txt
+	// del /q "b c.txt"
+	// del /q d.txt e.txt
+}
+
+func TestBatch(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Fixed, Variable []string
+		Q               quote.Quoting
+		Limit           quote.Limit
+		Batches         [][]string
+	}{
+		{
+			Name:     "unlimited",
+			Fixed:    []string{"rm"},
+			Variable: []string{"a", "b", "c"},
+			Limit:    quote.Limit{},
+			Batches:  [][]string{{"rm", "a", "b", "c"}},
+		},
+		{
+			Name:     "length",
+			Fixed:    []string{"rm"},
+			Variable: []string{"a", "b", "c"},
+			Limit:    quote.Limit{Length: len("rm a b")},
+			Batches:  [][]string{{"rm", "a", "b"}, {"rm", "c"}},
+		},
+		{
+			Name:     "args",
+			Fixed:    []string{"rm", "-f"},
+			Variable: []string{"a", "b", "c"},
+			Limit:    quote.Limit{Args: 3},
+			Batches:  [][]string{{"rm", "-f", "a"}, {"rm", "-f", "b"}, {"rm", "-f", "c"}},
+		},
+		{
+			Name:     "args;too many fixed",
+			Fixed:    []string{"rm", "-f"},
+			Variable: []string{"a", "b"},
+			Limit:    quote.Limit{Args: 2},
+			Batches:  nil,
+		},
+		{
+			Name:     "quoted length",
+			Fixed:    []string{"app"},
+			Variable: []string{`a"b`, "c"},
+			Q:        windows.Argv,
+			Limit:    quote.Limit{Length: len(`app "a\"b"`)},
+			Batches:  [][]string{{"app", `a"b`}, {"app", "c"}},
+		},
+		{
+			Name:     "cmd layer",
+			Fixed:    []string{"app"},
+			Variable: []string{"a&b", "c"},
+			Q:        quote.Chain(windows.Argv, windows.Cmd),
+			Limit:    quote.Limit{Length: len("app a^&b c")},
+			Batches:  [][]string{{"app", "a&b"}, {"app", "c"}},
+		},
+		{
+			Name:     "utf-16",
+			Fixed:    []string{"app"},
+			Variable: []string{"😇", "ж"},
+			Limit:    quote.Limit{Length: 8, UTF16: true},
+			Batches:  [][]string{{"app", "😇", "ж"}},
+		},
+		{
+			Name:     "too long",
+			Fixed:    []string{"app"},
+			Variable: []string{"a", strings.Repeat("b", 10), "c"},
+			Limit:    quote.Limit{Length: 5},
+			Batches:  [][]string{{"app", "a"}, {"app", strings.Repeat("b", 10)}, {"app", "c"}},
+		},
+		{
+			Name:    "no variable arguments",
+			Fixed:   []string{"app"},
+			Limit:   quote.WindowsLimit,
+			Batches: nil,
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			batches := quote.Batch(td.Fixed, td.Variable, td.Q, td.Limit)
+			if diff := cmp.Diff(td.Batches, batches); diff != "" {
+				t.Errorf("Batch() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBatch_Fits(t *testing.T) {
+	var files []string
+	for i := 0; i < 5000; i++ {
+		files = append(files, fmt.Sprintf(`C:\Program Files\App %d\file "%d".txt`, i, i))
+	}
+	for _, limit := range []quote.Limit{quote.WindowsLimit, quote.CmdLimit} {
+		var n int
+		for _, args := range quote.Batch([]string{"app.exe"}, files, windows.Argv, limit) {
+			if l := len(quote.Join(windows.Argv, args)); l > limit.Length {
+				t.Errorf("len(Join(Batch())) = %d; want <= %d", l, limit.Length)
+			}
+			n += len(args) - 1
+		}
+		if n != len(files) {
+			t.Errorf("Batch() returned %d arguments; want %d", n, len(files))
+		}
+	}
+}
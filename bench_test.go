@@ -0,0 +1,122 @@
+package quote_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	_ "github.com/sergeymakinen/go-quote/unix"
+	_ "github.com/sergeymakinen/go-quote/windows"
+)
+
+var benchInputs = []struct {
+	Name, Input string
+
+	// QuoteAllocs and UnquoteAllocs are the maximum numbers of allocations
+	// made by Quote and Unquote of the input by every dialect.
+	QuoteAllocs, UnquoteAllocs float64
+}{
+	{
+		Name:          "short ASCII",
+		Input:         "build/output.txt",
+		QuoteAllocs:   1,
+		UnquoteAllocs: 2,
+	},
+	{
+		Name:          "short Unicode",
+		Input:         "сборка/вывод.txt",
+		QuoteAllocs:   1,
+		UnquoteAllocs: 2,
+	},
+	{
+		Name:          "long ASCII",
+		Input:         strings.Repeat(`C:\Program Files\App\it's "quoted" & $escaped.txt `, 32),
+		QuoteAllocs:   3,
+		UnquoteAllocs: 2,
+	},
+	{
+		Name:          "long Unicode",
+		Input:         strings.Repeat(`C:\Программы\«ταБЬℓσ» "в кавычках" & $экранировано.txt `, 32),
+		QuoteAllocs:   3,
+		UnquoteAllocs: 2,
+	},
+}
+
+// benchDialects returns the registered dialects under their qualified names.
+func benchDialects() []quote.Dialect {
+	var ds []quote.Dialect
+	for _, d := range quote.Dialects() {
+		if strings.Contains(d.Name, ".") {
+			ds = append(ds, d)
+		}
+	}
+	return ds
+}
+
+func TestAllocs(t *testing.T) {
+	for _, d := range benchDialects() {
+		for _, in := range benchInputs {
+			t.Run(d.Name+"/"+in.Name, func(t *testing.T) {
+				if n := testing.AllocsPerRun(100, func() {
+					d.Quoting.MustQuote(in.Input)
+				}); n != 0 {
+					t.Errorf("MustQuote() allocs = %v; want 0", n)
+				}
+				if n := testing.AllocsPerRun(100, func() {
+					d.Quoting.Quote(in.Input)
+				}); n > in.QuoteAllocs {
+					t.Errorf("Quote() allocs = %v; want <= %v", n, in.QuoteAllocs)
+				}
+				quoted := d.Quoting.Quote(in.Input)
+				if n := testing.AllocsPerRun(100, func() {
+					d.Quoting.Unquote(quoted)
+				}); n > in.UnquoteAllocs {
+					t.Errorf("Unquote() allocs = %v; want <= %v", n, in.UnquoteAllocs)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkMustQuote(b *testing.B) {
+	for _, d := range benchDialects() {
+		for _, in := range benchInputs {
+			b.Run(d.Name+"/"+in.Name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(in.Input)))
+				for i := 0; i < b.N; i++ {
+					d.Quoting.MustQuote(in.Input)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkQuote(b *testing.B) {
+	for _, d := range benchDialects() {
+		for _, in := range benchInputs {
+			b.Run(d.Name+"/"+in.Name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(in.Input)))
+				for i := 0; i < b.N; i++ {
+					d.Quoting.Quote(in.Input)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkUnquote(b *testing.B) {
+	for _, d := range benchDialects() {
+		for _, in := range benchInputs {
+			b.Run(d.Name+"/"+in.Name, func(b *testing.B) {
+				quoted := d.Quoting.Quote(in.Input)
+				b.ReportAllocs()
+				b.SetBytes(int64(len(quoted)))
+				for i := 0; i < b.N; i++ {
+					d.Quoting.Unquote(quoted)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package quote
+
+import "fmt"
+
+type chain []Quoting
+
+// Chain returns a Quoting that quotes strings with inner
+// and then with every one of outer in order, and unquotes them in reverse order.
+//
+// It's useful for strings passing through several layers of interpretation,
+// like a Windows program argument passed via cmd.exe:
+//
+//	quote.Chain(windows.Argv, windows.Cmd)
+//
+// Or a script passed to sh -c via ssh:
+//
+//	quote.Chain(unix.SingleQuote, unix.SingleQuote)
+//
+// MustQuote reports whether any of the layers must quote a string.
+//
+// Errors of type *SyntaxError returned by Unquote are prefixed with the index of the failed layer,
+// inner being 0, and their inputs and offsets are those of that layer.
+func Chain(inner Quoting, outer ...Quoting) Quoting {
+	return append(chain{inner}, outer...)
+}
+
+func (c chain) MustQuote(s string) bool {
+	for _, q := range c {
+		if q.MustQuote(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c chain) Quote(s string) string {
+	for _, q := range c {
+		s = q.Quote(s)
+	}
+	return s
+}
+
+func (c chain) Unquote(s string) (string, error) {
+	for i := len(c) - 1; i >= 0; i-- {
+		var err error
+		if s, err = c[i].Unquote(s); err != nil {
+			if e, ok := err.(*SyntaxError); ok {
+				le := *e
+				le.Msg = fmt.Sprintf("layer %d: %s", i, e.Msg)
+				return "", &le
+			}
+			return "", err
+		}
+	}
+	return s, nil
+}
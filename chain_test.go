@@ -0,0 +1,129 @@
+package quote_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleChain() {
+	filename := `Long File With 'Single' & "Double" Quotes.txt`
+	q := quote.Chain(windows.Argv, windows.Cmd)
+	quoted := q.Quote(filename)
+	fmt.Println([]string{
+		"cmd.exe",
+		"/C",
+		fmt.Sprintf("callme.exe %s", quoted),
+	})
+	unquoted, _ := q.Unquote(quoted)
+	fmt.Println(unquoted)
+	// Output:
+	// [cmd.exe /C callme.exe ^"Long^ File^ With^ ^'Single^'^ ^&^ \^"Double\^"^ Quotes.txt^"]
+	// Long File With 'Single' & "Double" Quotes.txt
+}
+
+func TestChain_Quote_Unquote(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Q      quote.Quoting
+		Input  string
+		Output string
+	}{
+		{
+			Name:   "unix.SingleQuote",
+			Q:      quote.Chain(unix.SingleQuote),
+			Input:  "it's",
+			Output: `'it'"'"'s'`,
+		},
+		{
+			Name:   "unix.SingleQuote;unix.SingleQuote",
+			Q:      quote.Chain(unix.SingleQuote, unix.SingleQuote),
+			Input:  "it's",
+			Output: `''"'"'it'"'"'"'"'"'"'"'"'s'"'"''`,
+		},
+		{
+			Name:   "unix.ANSIC;unix.SingleQuote;unix.DoubleQuote",
+			Q:      quote.Chain(unix.ANSIC, unix.SingleQuote, unix.DoubleQuote),
+			Input:  "a\nb",
+			Output: `"'\$'\"'\"'a\\nb'\"'\"''"`,
+		},
+		{
+			Name:   "windows.PSSingleQuote;windows.Argv",
+			Q:      quote.Chain(windows.PSSingleQuote, windows.Argv),
+			Input:  `it's "quoted"`,
+			Output: `"'it''s \"quoted\"'"`,
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			quoted := td.Q.Quote(td.Input)
+			testutil.TestDiff(t, "Quote()", td.Output, quoted)
+			unquoted, err := td.Q.Unquote(quoted)
+			if err != nil {
+				t.Fatalf("Unquote() = _, %v; want nil", err)
+			}
+			testutil.TestDiff(t, "Unquote()", td.Input, unquoted)
+		})
+	}
+}
+
+func TestChain_MustQuote(t *testing.T) {
+	q := quote.Chain(windows.Argv, windows.Cmd)
+	tests := []struct {
+		Input string
+		Must  bool
+	}{
+		{Input: "abc", Must: false},
+		{Input: "a b", Must: true},
+		{Input: "a&b", Must: true},
+	}
+	for _, td := range tests {
+		if must := q.MustQuote(td.Input); must != td.Must {
+			t.Errorf("MustQuote(%q) = %v; want %v", td.Input, must, td.Must)
+		}
+	}
+}
+
+func TestChain_Unquote_ShouldFail(t *testing.T) {
+	tests := []struct {
+		Name, Input string
+		Err         error
+	}{
+		{
+			Name:  "outer layer",
+			Input: `'a'b`,
+			Err: &quote.SyntaxError{
+				Msg:     "layer 1: character U+0062 'b' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.SingleQuote",
+				Input:   `'a'b`,
+				Offset:  3,
+			},
+		},
+		{
+			Name:  "inner layer",
+			Input: `'"a"b'`,
+			Err: &quote.SyntaxError{
+				Msg:     "layer 0: character U+0062 'b' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.Argv",
+				Input:   `"a"b`,
+				Offset:  3,
+			},
+		},
+	}
+	q := quote.Chain(windows.Argv, unix.SingleQuote)
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			_, err := q.Unquote(td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Errorf("Unquote() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package quote
+
+import "strconv"
+
+// Context is a position in a command line or script where a quoted string is placed.
+// Each context has its own special characters, so a string is quoted differently
+// depending on where it's placed.
+type Context int
+
+// Contexts.
+const (
+	// ContextArgument is a standalone word, like a command-line argument,
+	// quoted only if needed as by Join.
+	ContextArgument Context = iota + 1
+
+	// ContextAssignment is a value of a variable or property assignment
+	// following NAME=, like FOO=value in shells or PROPERTY=value for msiexec.
+	ContextAssignment
+
+	// ContextHeredoc is a part of the body of a here-document (<<EOF) in Unix-based shells
+	// or a here-string (@"…"@) in PowerShell.
+	// Here-documents end with a line equal to the delimiter, which must not be a line of the body:
+	// as the delimiter is unknown, Unix-based quotings reject values with newlines,
+	// which unix.QuoteHeredoc can escape instead.
+	ContextHeredoc
+
+	// ContextComment is a part of a comment ending with the line.
+	ContextComment
+
+	// ContextPattern is a pattern, like a word of a case statement or the right side
+	// of the PowerShell -like operator, that must match the string literally.
+	ContextPattern
+
+	// ContextParameterWord is a word of a parameter expansion, like ${FOO:-word},
+	// outside of double quotes.
+	ContextParameterWord
+)
+
+var contexts = [...]string{
+	ContextArgument:      "argument",
+	ContextAssignment:    "assignment value",
+	ContextHeredoc:       "here-document body",
+	ContextComment:       "comment",
+	ContextPattern:       "pattern",
+	ContextParameterWord: "parameter expansion word",
+}
+
+func (c Context) String() string {
+	if c > 0 && int(c) < len(contexts) {
+		return contexts[c]
+	}
+	return "Context(" + strconv.Itoa(int(c)) + ")"
+}
+
+// ContextQuoting quotes textual command-line arguments and variables
+// placed in contexts other than standalone words.
+// Quotings of the unix and windows packages implement it.
+type ContextQuoting interface {
+	Quoting
+
+	// QuoteContext returns s quoted such that it appears correctly in context c,
+	// escaping only the characters special in c,
+	// or an error of type *UnrepresentableError if s can't appear correctly in c
+	// or c is not supported.
+	QuoteContext(c Context, s string) (string, error)
+}
+
+// QuoteContext returns s quoted with q such that it appears correctly in context c.
+// If q doesn't implement ContextQuoting, only ContextArgument is supported.
+func QuoteContext(q Quoting, c Context, s string) (string, error) {
+	if cq, ok := q.(ContextQuoting); ok {
+		return cq.QuoteContext(c, s)
+	}
+	if c != ContextArgument {
+		return "", &UnrepresentableError{
+			Msg:   "unsupported context " + c.String(),
+			Input: s,
+		}
+	}
+	if s != "" && !q.MustQuote(s) {
+		return s, nil
+	}
+	if sq, ok := q.(StrictQuoting); ok {
+		return sq.QuoteStrict(s)
+	}
+	return q.Quote(s), nil
+}
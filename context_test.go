@@ -0,0 +1,78 @@
+package quote_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/unix"
+)
+
+func ExampleQuoteContext() {
+	for _, c := range []quote.Context{quote.ContextArgument, quote.ContextAssignment, quote.ContextHeredoc} {
+		s, _ := quote.QuoteContext(unix.SingleQuote, c, "*.txt $HOME")
+		fmt.Printf("%v: %s\n", c, s)
+	}
+	s, _ := quote.QuoteContext(unix.SingleQuote, quote.ContextAssignment, "*.txt")
+	fmt.Println("FILES=" + s)
+	// Output:
+	// argument: '*.txt $HOME'
+	// assignment value: '*.txt $HOME'
+	// here-document body: *.txt \$HOME
+	// FILES=*.txt
+}
+
+func TestQuoteContext(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Context quote.Context
+		Input   string
+		Output  string
+		Err     error
+	}{
+		{
+			Name:    "argument",
+			Context: quote.ContextArgument,
+			Input:   "abc",
+			Output:  "abc",
+		},
+		{
+			Name:    "argument;empty",
+			Context: quote.ContextArgument,
+			Input:   "",
+			Output:  "''",
+		},
+		{
+			Name:    "argument;quoted",
+			Context: quote.ContextArgument,
+			Input:   "a b",
+			Output:  "'a b'",
+		},
+		{
+			Name:    "unsupported",
+			Context: quote.ContextHeredoc,
+			Input:   "a b",
+			Err: &quote.UnrepresentableError{
+				Msg:   "unsupported context here-document body",
+				Input: "a b",
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			// lossyQuoting doesn't implement quote.ContextQuoting.
+			s, err := quote.QuoteContext(lossyQuoting{unix.SingleQuote}, td.Context, td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuoteContext() mismatch (-want +got):\n%s", diff)
+			}
+			testutil.TestDiff(t, "QuoteContext()", td.Output, s)
+		})
+	}
+}
+
+func TestContext_String(t *testing.T) {
+	testutil.TestDiff(t, "String()", "assignment value", quote.ContextAssignment.String())
+	testutil.TestDiff(t, "String()", "Context(0)", quote.Context(0).String())
+}
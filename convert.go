@@ -0,0 +1,50 @@
+package quote
+
+import "fmt"
+
+// Convert unquotes s with from and quotes the result with to.
+//
+// If s is not a valid quoted string, Convert returns the error returned by from.
+// If to can't represent the unquoted value, Convert returns an error of type *UnrepresentableError:
+// either the one returned by QuoteStrict if to implements StrictQuoting
+// or the one describing where the value quoted by to stops unquoting to the original value.
+func Convert(s string, from, to Quoting) (string, error) {
+	unquoted, err := from.Unquote(s)
+	if err != nil {
+		return "", err
+	}
+	return quoteExact(to, unquoted)
+}
+
+// quoteExact quotes s with q, making sure that the result unquotes back to s.
+func quoteExact(q Quoting, s string) (string, error) {
+	var quoted string
+	if sq, ok := q.(StrictQuoting); ok {
+		var err error
+		if quoted, err = sq.QuoteStrict(s); err != nil {
+			return "", err
+		}
+	} else {
+		quoted = q.Quote(s)
+	}
+	unquoted, err := q.Unquote(quoted)
+	if err == nil && unquoted == s {
+		return quoted, nil
+	}
+	i := 0
+	for i < len(s) && i < len(unquoted) && s[i] == unquoted[i] {
+		i++
+	}
+	e := &UnrepresentableError{
+		Msg:    "quoted string doesn't unquote to the original one",
+		Input:  s,
+		Offset: i,
+	}
+	if i < len(s) {
+		e.Msg = fmt.Sprintf("byte %#02x doesn't survive quoting", s[i])
+	}
+	if st, ok := q.(fmt.Stringer); ok {
+		e.Dialect = st.String()
+	}
+	return "", e
+}
@@ -0,0 +1,138 @@
+package quote_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleConvert() {
+	s, err := quote.Convert(`'it'"'"'s'`, unix.SingleQuote, windows.PSSingleQuote)
+	if err != nil {
+		return
+	}
+	fmt.Println(s)
+	// Output:
+	// 'it''s'
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		Name     string
+		From, To quote.Quoting
+		Input    string
+		Output   string
+	}{
+		{
+			Name:   "unix.SingleQuote;windows.PSSingleQuote",
+			From:   unix.SingleQuote,
+			To:     windows.PSSingleQuote,
+			Input:  `'a b'"'"'c'`,
+			Output: `'a b''c'`,
+		},
+		{
+			Name:   "windows.Argv;unix.ANSIC",
+			From:   windows.Argv,
+			To:     unix.ANSIC,
+			Input:  `"a\"b	c"`,
+			Output: `$'a\"b\tc'`,
+		},
+		{
+			Name:   "unix.ANSIC;windows.PwshDoubleQuote",
+			From:   unix.ANSIC,
+			To:     windows.PwshDoubleQuote,
+			Input:  `$'a\x00b\x1B'`,
+			Output: "\"a`0b`e\"",
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			s, err := quote.Convert(td.Input, td.From, td.To)
+			if err != nil {
+				t.Fatalf("Convert() = _, %v; want nil", err)
+			}
+			testutil.TestDiff(t, "Convert()", td.Output, s)
+		})
+	}
+}
+
+func TestConvert_ShouldFail(t *testing.T) {
+	tests := []struct {
+		Name     string
+		From, To quote.Quoting
+		Input    string
+		Err      error
+	}{
+		{
+			Name:  "invalid input",
+			From:  unix.SingleQuote,
+			To:    windows.Argv,
+			Input: `'a`,
+			Err: &quote.SyntaxError{
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "unix.SingleQuote",
+				Input:   `'a`,
+				Offset:  2,
+			},
+		},
+		{
+			Name:  "NUL",
+			From:  unix.ANSIC,
+			To:    windows.Argv,
+			Input: `$'a\0'`,
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "windows.Argv",
+				Input:   "a\x00",
+				Offset:  1,
+			},
+		},
+		{
+			Name:  "newline",
+			From:  unix.DoubleQuote,
+			To:    windows.Cmd,
+			Input: "\"a\nb\"",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+000A",
+				Dialect: "windows.Cmd",
+				Input:   "a\nb",
+				Offset:  1,
+			},
+		},
+		{
+			Name:  "no round trip",
+			From:  unix.SingleQuote,
+			To:    lossyQuoting{unix.SingleQuote},
+			Input: "'ab\xFF'",
+			Err: &quote.UnrepresentableError{
+				Msg:    "byte 0xff doesn't survive quoting",
+				Input:  "ab\xFF",
+				Offset: 2,
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			_, err := quote.Convert(td.Input, td.From, td.To)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Errorf("Convert() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// lossyQuoting replaces invalid UTF-8 sequences with U+FFFD when quoting.
+type lossyQuoting struct {
+	quote.Quoting
+}
+
+func (q lossyQuoting) Quote(s string) string {
+	return q.Quoting.Quote(strings.ToValidUTF8(s, "\uFFFD"))
+}
@@ -0,0 +1,93 @@
+package quote
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Match is a Quoting that accepts a quoted string.
+type Match struct {
+	Name    string  // name of the dialect, if known
+	Quoting Quoting // quoting that accepts the string
+	Value   string  // string value that the string quotes
+	Score   int     // how idiomatic the string looks for the quoting, higher is better
+}
+
+// Detect unquotes s with each of candidates, or every registered dialect
+// if there are no candidates, and returns the ones that accept s
+// ranked by how idiomatic s looks for them.
+//
+// A quoting scores higher the more of s is quoting syntax rather than the value itself,
+// with a bonus if quoting the value again reproduces s exactly.
+// For example, the following strings are ranked best for
+// unix.ANSIC, windows.PSSingleQuote and windows.PSDoubleQuote respectively:
+//
+//	$'…'
+//	'it''s'
+//	"a`tb"
+//
+// Quotings that accept s but leave it unchanged, like windows.Cmd for strings without carets,
+// don't match, as s isn't quoted for them.
+//
+// Matches with equal scores keep the order of candidates.
+//
+// Aliases of registered dialects, like "bash", are skipped in favor of the dialects they refer to.
+func Detect(s string, candidates ...Quoting) []Match {
+	var matches []Match
+	if len(candidates) > 0 {
+		for _, q := range candidates {
+			if m, ok := detect(s, q); ok {
+				if st, ok := q.(fmt.Stringer); ok {
+					m.Name = st.String()
+				}
+				matches = append(matches, m)
+			}
+		}
+	} else {
+		for _, d := range Dialects() {
+			if isAlias(d) {
+				continue
+			}
+			if m, ok := detect(s, d.Quoting); ok {
+				m.Name = d.Name
+				matches = append(matches, m)
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// canonicalBonus is added to the score of a quoting that reproduces a string exactly.
+const canonicalBonus = 2
+
+func detect(s string, q Quoting) (Match, bool) {
+	v, err := q.Unquote(s)
+	if err != nil || v == s {
+		return Match{}, false
+	}
+	m := Match{
+		Quoting: q,
+		Value:   v,
+	}
+	if len(v) < len(s) {
+		m.Score = len(s) - len(v)
+	}
+	if q.Quote(v) == s {
+		m.Score += canonicalBonus
+	}
+	return m, true
+}
+
+// isAlias reports whether d is registered under another name,
+// as reported by its Quoting.
+func isAlias(d Dialect) bool {
+	st, ok := d.Quoting.(fmt.Stringer)
+	if !ok || st.String() == d.Name {
+		return false
+	}
+	_, ok = Lookup(st.String())
+	return ok
+}
@@ -0,0 +1,73 @@
+package quote_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleDetect() {
+	for _, s := range []string{`$'…'`, `'it''s'`, "\"a`tb\""} {
+		m := quote.Detect(s)[0]
+		fmt.Printf("%s: %q\n", m.Name, m.Value)
+	}
+	// Output:
+	// unix.ANSIC: "…"
+	// windows.PSSingleQuote: "it's"
+	// windows.PSDoubleQuote: "a\tb"
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		Input, Name string
+	}{
+		{Input: `'it'"'"'s'`, Name: "unix.SingleQuote"},
+		{Input: `"a \$b"`, Name: "unix.DoubleQuote"},
+		{Input: `$'a\tb'`, Name: "unix.ANSIC"},
+		{Input: `"a\b"`, Name: "windows.Argv"},
+		{Input: `a^ ^&^ b`, Name: "windows.Cmd"},
+		{Input: `"a""b"`, Name: "windows.Msiexec"},
+		{Input: `'a''b'`, Name: "windows.PSSingleQuote"},
+		{Input: "\"`$a\"", Name: "windows.PSDoubleQuote"},
+		{Input: "\"`u{01}\"", Name: "windows.PwshDoubleQuote"},
+	}
+	for _, td := range tests {
+		t.Run(td.Input, func(t *testing.T) {
+			matches := quote.Detect(td.Input)
+			if len(matches) == 0 {
+				t.Fatalf("Detect(%q) is empty", td.Input)
+			}
+			if matches[0].Name != td.Name {
+				t.Errorf("Detect(%q)[0].Name = %q; want %q", td.Input, matches[0].Name, td.Name)
+			}
+			for i := 1; i < len(matches); i++ {
+				if matches[i-1].Score < matches[i].Score {
+					t.Errorf("Detect(%q) is not sorted: %d < %d", td.Input, matches[i-1].Score, matches[i].Score)
+				}
+			}
+		})
+	}
+}
+
+func TestDetect_Candidates(t *testing.T) {
+	matches := quote.Detect(`'a b'`, windows.Argv, unix.SingleQuote, windows.PSSingleQuote)
+	var names []string
+	for _, m := range matches {
+		names = append(names, m.Name)
+	}
+	if len(names) != 2 || names[0] != "unix.SingleQuote" || names[1] != "windows.PSSingleQuote" {
+		t.Errorf("Detect() names = %q; want [unix.SingleQuote windows.PSSingleQuote]", names)
+	}
+	if matches := quote.Detect(`'a`, unix.SingleQuote); len(matches) != 0 {
+		t.Errorf("Detect() = %v; want empty", matches)
+	}
+}
+
+func TestDetect_Unquoted(t *testing.T) {
+	if matches := quote.Detect("abc"); len(matches) != 0 {
+		t.Errorf("Detect() = %v; want empty", matches)
+	}
+}
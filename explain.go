@@ -0,0 +1,47 @@
+package quote
+
+import "strconv"
+
+// Explainer breaks quoted textual command-line arguments and variables into annotated spans.
+type Explainer interface {
+	Quoting
+
+	// Explain interprets s as a quoted string like Unquote does,
+	// returning the spans it consists of in order.
+	// Concatenated values of the spans make the string value that s quotes.
+	Explain(s string) ([]Span, error)
+}
+
+// SpanKind is a kind of Span.
+type SpanKind int
+
+// Kinds of spans.
+const (
+	SpanLiteral          SpanKind = iota + 1 // text taken literally
+	SpanOpenQuote                            // quote starting a quoted string
+	SpanCloseQuote                           // quote ending a quoted string
+	SpanEscape                               // escape sequence
+	SpanLineContinuation                     // escaped newline removed from the value
+)
+
+var spanKinds = [...]string{
+	SpanLiteral:          "literal",
+	SpanOpenQuote:        "open quote",
+	SpanCloseQuote:       "close quote",
+	SpanEscape:           "escape",
+	SpanLineContinuation: "line continuation",
+}
+
+func (k SpanKind) String() string {
+	if k > 0 && int(k) < len(spanKinds) {
+		return spanKinds[k]
+	}
+	return "SpanKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Span is a part of a quoted string.
+type Span struct {
+	Start, End int      // byte range of the span in the quoted string
+	Kind       SpanKind // kind of span
+	Value      string   // part of the string value contributed by the span
+}
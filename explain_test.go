@@ -0,0 +1,107 @@
+package quote_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleExplainer() {
+	s := `$'it\'s\x21'`
+	spans, err := unix.ANSIC.(quote.Explainer).Explain(s)
+	if err != nil {
+		return
+	}
+	for _, span := range spans {
+		fmt.Printf("%-11s %-6s %q\n", span.Kind, s[span.Start:span.End], span.Value)
+	}
+	// Output:
+	// open quote  $'     ""
+	// literal     it     "it"
+	// escape      \'     "'"
+	// literal     s      "s"
+	// escape      \x21   "!"
+	// close quote '      ""
+}
+
+func TestExplainer_InputTests(t *testing.T) {
+	dialects := []quote.Quoting{
+		unix.SingleQuote,
+		unix.DoubleQuote,
+		unix.ANSIC,
+		windows.Argv,
+		windows.Cmd,
+		windows.Msiexec,
+		windows.PSSingleQuote,
+		windows.PSDoubleQuote,
+		windows.PwshDoubleQuote,
+	}
+	for _, q := range dialects {
+		t.Run(fmt.Sprint(q), func(t *testing.T) {
+			for _, it := range testutil.InputTests('"', '\'', '`', '$', '^', '\\') {
+				quoted := q.Quote(it.Input)
+				unquoted, err := q.Unquote(quoted)
+				if err != nil {
+					t.Fatalf("%s: Unquote() = _, %v; want nil", it.Name, err)
+				}
+				spans, err := q.(quote.Explainer).Explain(quoted)
+				if err != nil {
+					t.Fatalf("%s: Explain() = _, %v; want nil", it.Name, err)
+				}
+				value, end := "", 0
+				for _, span := range spans {
+					if span.Start != end || span.End <= span.Start {
+						t.Fatalf("%s: Explain() returned span [%d, %d) after [_, %d)", it.Name, span.Start, span.End, end)
+					}
+					value += span.Value
+					end = span.End
+				}
+				if end != len(quoted) {
+					t.Errorf("%s: Explain() spans end at %d; want %d", it.Name, end, len(quoted))
+				}
+				testutil.TestDiff(t, it.Name+": Explain() value", unquoted, value)
+			}
+		})
+	}
+}
+
+func TestExplainer_ShouldFail(t *testing.T) {
+	tests := []struct {
+		Q     quote.Quoting
+		Input string
+	}{
+		{Q: unix.SingleQuote, Input: "'a"},
+		{Q: unix.DoubleQuote, Input: `"\`},
+		{Q: unix.ANSIC, Input: `$'\c+'`},
+		{Q: windows.Argv, Input: `"a"b`},
+		{Q: windows.Msiexec, Input: `"a`},
+		{Q: windows.PSSingleQuote, Input: "a'"},
+		{Q: windows.PwshDoubleQuote, Input: "\"`u{}\""},
+	}
+	for _, td := range tests {
+		t.Run(fmt.Sprint(td.Q), func(t *testing.T) {
+			_, expected := td.Q.Unquote(td.Input)
+			spans, err := td.Q.(quote.Explainer).Explain(td.Input)
+			if err == nil {
+				t.Fatalf("Explain() = %v, nil; want %v", spans, expected)
+			}
+			if diff := cmp.Diff(expected, err); diff != "" {
+				t.Errorf("Explain() error mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSpanKind_String(t *testing.T) {
+	if s := quote.SpanLineContinuation.String(); s != "line continuation" {
+		t.Errorf("SpanLineContinuation.String() = %q; want %q", s, "line continuation")
+	}
+	if s := quote.SpanKind(0).String(); s != "SpanKind(0)" {
+		t.Errorf("SpanKind(0).String() = %q; want %q", s, "SpanKind(0)")
+	}
+}
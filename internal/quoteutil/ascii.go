@@ -0,0 +1,54 @@
+// Package quoteutil implements helpers shared by quotings of the unix and windows packages.
+package quoteutil
+
+import "unicode/utf8"
+
+// ASCIISet is a set of ASCII characters.
+type ASCIISet [utf8.RuneSelf]bool
+
+func NewASCIISet(chars string) *ASCIISet {
+	var as ASCIISet
+	for i := 0; i < len(chars); i++ {
+		as[chars[i]] = true
+	}
+	return &as
+}
+
+// Contains reports whether c is in as.
+func (as *ASCIISet) Contains(c byte) bool {
+	return c < utf8.RuneSelf && as[c]
+}
+
+// Index returns the index of the first byte of s in as, or -1 if there is none.
+func (as *ASCIISet) Index(s string) int {
+	for i := 0; i < len(s); i++ {
+		if as.Contains(s[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Skip returns the number of leading bytes of s that are ASCII characters not in as.
+func (as *ASCIISet) Skip(s string) int {
+	i := 0
+	for i < len(s) && s[i] < utf8.RuneSelf && !as[s[i]] {
+		i++
+	}
+	return i
+}
+
+func IsHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+const upperHex = "0123456789ABCDEF"
+
+// AppendHex appends prefix followed by n upper-case hexadecimal digits of v to dst.
+func AppendHex(dst []byte, prefix string, v rune, n int) []byte {
+	dst = append(dst, prefix...)
+	for n--; n >= 0; n-- {
+		dst = append(dst, upperHex[v>>(4*n)&0xF])
+	}
+	return dst
+}
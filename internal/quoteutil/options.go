@@ -0,0 +1,71 @@
+package quoteutil
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sergeymakinen/go-quote"
+)
+
+// Options are options of a quoting customized with the Option type of the unix or windows package.
+type Options struct {
+	Safe, Unsafe        string
+	Always, DisplaySafe bool
+}
+
+// NewOptions returns Options with opts applied.
+func NewOptions[Option ~func(*Options)](opts []Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// MustQuote reports whether s must be quoted according to o
+// and, for characters o doesn't decide on, mustQuote.
+func (o Options) MustQuote(s string, mustQuote func(s string) bool) bool {
+	switch {
+	case o.Always, strings.ContainsAny(s, o.Unsafe), o.DisplaySafe && IndexHidden(s) >= 0:
+		return true
+	case o.Safe != "":
+		s = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(o.Safe, r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	return mustQuote(s)
+}
+
+// CheckDisplaySafe returns an error if o is display-safe and s contains
+// bidirectional controls or invisible characters, that the quoting named name can't escape.
+func (o Options) CheckDisplaySafe(name, s string) error {
+	if !o.DisplaySafe {
+		return nil
+	}
+	if i := IndexHidden(s); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(s[i:])
+		return Unrepresentable(name, s, i, fmt.Sprintf("%v character %U", quote.SuspicionOf(r), r))
+	}
+	return nil
+}
+
+// IsHidden reports whether r is a bidirectional control or an invisible character.
+func IsHidden(r rune) bool {
+	k := quote.SuspicionOf(r)
+	return k == quote.SuspicionBidi || k == quote.SuspicionInvisible
+}
+
+// IndexHidden returns the index of the first bidirectional control or invisible character in s,
+// or -1 if there is none.
+func IndexHidden(s string) int {
+	for i, r := range s {
+		if IsHidden(r) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,59 @@
+package quoteutil
+
+import "github.com/sergeymakinen/go-quote"
+
+// span is a quote.Span with its value stored as a range of the unquoted buffer.
+type span struct {
+	kind                 quote.SpanKind
+	start, end           int
+	valueStart, valueEnd int
+}
+
+// SpanList records spans of a quoted string while it's unquoted.
+// Its methods do nothing on a nil list, so unquoters only record spans when asked to.
+type SpanList struct {
+	spans []span
+}
+
+// Add records a span of kind k covering s[start:end] of the quoted string
+// and dst[valueStart:valueEnd] of the unquoted buffer,
+// merging adjacent literal spans and skipping empty ones.
+func (l *SpanList) Add(k quote.SpanKind, start, end, valueStart, valueEnd int) {
+	if l == nil || start == end {
+		return
+	}
+	if n := len(l.spans); n > 0 && k == quote.SpanLiteral {
+		if last := &l.spans[n-1]; last.kind == k && last.end == start && last.valueEnd == valueStart {
+			last.end, last.valueEnd = end, valueEnd
+			return
+		}
+	}
+	l.spans = append(l.spans, span{kind: k, start: start, end: end, valueStart: valueStart, valueEnd: valueEnd})
+}
+
+// AddQuote records an opening quote if open is true or a closing quote otherwise.
+func (l *SpanList) AddQuote(open bool, start, end, value int) {
+	if open {
+		l.Add(quote.SpanOpenQuote, start, end, value, value)
+	} else {
+		l.Add(quote.SpanCloseQuote, start, end, value, value)
+	}
+}
+
+// Explain returns the recorded spans of s with values from dst,
+// or err if q failed to unquote s.
+func (l *SpanList) Explain(dst []byte, err error, q Dialect, s string) ([]quote.Span, error) {
+	if err != nil {
+		return nil, SyntaxError(err, q, s, 0)
+	}
+	spans := make([]quote.Span, len(l.spans))
+	for i, sp := range l.spans {
+		spans[i] = quote.Span{
+			Start: sp.start,
+			End:   sp.end,
+			Kind:  sp.kind,
+			Value: string(dst[sp.valueStart:sp.valueEnd]),
+		}
+	}
+	return spans, nil
+}
@@ -0,0 +1,95 @@
+package quoteutil
+
+import "github.com/sergeymakinen/go-quote"
+
+// Mode is how Dialect.UnquoteMode treats characters outside of quoted strings.
+type Mode int
+
+const (
+	// UnquoteAll requires the whole string to be quoted.
+	UnquoteAll Mode = iota
+
+	// UnquoteWord takes characters outside of quoted strings literally
+	// and stops before the first unquoted blank.
+	UnquoteWord
+
+	// UnquotePrefix stops before the first character outside of quoted strings.
+	UnquotePrefix
+)
+
+// Dialect is a quoting of the unix or windows package.
+type Dialect interface {
+	// String returns the name of the dialect.
+	String() string
+
+	// UnquoteMode unquotes the leading part of s according to mode, appending its value to dst
+	// and returning the extended buffer and the number of bytes consumed.
+	UnquoteMode(dst []byte, s string, mode Mode) ([]byte, int, error)
+}
+
+// SyntaxError fills in the dialect and input of err if it's a *quote.SyntaxError,
+// moving its offset by offset bytes.
+func SyntaxError(err error, q Dialect, s string, offset int) error {
+	if e, ok := err.(*quote.SyntaxError); ok {
+		e.Dialect = q.String()
+		e.Input = s
+		e.Offset += offset
+	}
+	return err
+}
+
+func Unrepresentable(name, s string, offset int, msg string) error {
+	return &quote.UnrepresentableError{
+		Msg:     msg,
+		Dialect: name,
+		Input:   s,
+		Offset:  offset,
+	}
+}
+
+func AppendUnquote(dst []byte, s string, q Dialect) ([]byte, error) {
+	b, _, err := q.UnquoteMode(dst, s, UnquoteAll)
+	if err != nil {
+		return dst, SyntaxError(err, q, s, 0)
+	}
+	return b, nil
+}
+
+func QuotedPrefix(s string, q Dialect) (string, string, error) {
+	if s == "" {
+		return "", "", &quote.SyntaxError{
+			Msg:     "missing quoted string",
+			Kind:    quote.ErrUnterminated,
+			Dialect: q.String(),
+			Offset:  0,
+		}
+	}
+	b, n, err := q.UnquoteMode(nil, s, UnquotePrefix)
+	if err != nil {
+		return "", "", SyntaxError(err, q, s, 0)
+	}
+	return string(b), s[n:], nil
+}
+
+// Split splits s into words unquoted with q, separated by characters isBlank reports true for.
+func Split(s string, q Dialect, isBlank func(c byte) bool) ([]string, error) {
+	var (
+		args []string
+		buf  []byte
+	)
+	for i := 0; ; {
+		for i < len(s) && isBlank(s[i]) {
+			i++
+		}
+		if i == len(s) {
+			return args, nil
+		}
+		b, n, err := q.UnquoteMode(buf[:0], s[i:], UnquoteWord)
+		if err != nil {
+			return nil, SyntaxError(err, q, s, i)
+		}
+		args = append(args, string(b))
+		buf = b
+		i += n
+	}
+}
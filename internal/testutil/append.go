@@ -0,0 +1,83 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+)
+
+// AppendTest is a quoting checked by TestAppend, TestAppendAllocs and the append benchmarks.
+// Invalid, if not empty, is a string AppendUnquote must fail on.
+type AppendTest struct {
+	Name    string
+	Q       quote.Appender
+	Invalid string
+}
+
+const appendInput = "Testing «ταБЬℓσ»: 'a' \"b\" $c `d` \\e\x01\u0378"
+
+func TestAppend(t *testing.T, tests []AppendTest) {
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			quoted := td.Q.AppendQuote([]byte("prefix:"), appendInput)
+			TestDiff(t, td.Name+".AppendQuote()", "prefix:"+td.Q.Quote(appendInput), string(quoted))
+			unquoted, err := td.Q.AppendUnquote([]byte("prefix:"), string(quoted[len("prefix:"):]))
+			if err != nil {
+				t.Fatalf("%s.AppendUnquote() = _, %v; want nil", td.Name, err)
+			}
+			TestDiff(t, td.Name+".AppendUnquote()", "prefix:"+appendInput, string(unquoted))
+			if td.Invalid == "" {
+				return
+			}
+			unquoted, err = td.Q.AppendUnquote([]byte("prefix:"), td.Invalid)
+			if err == nil {
+				t.Fatalf("%s.AppendUnquote() = _, nil; want error", td.Name)
+			}
+			TestDiff(t, td.Name+".AppendUnquote()", "prefix:", string(unquoted))
+		})
+	}
+}
+
+func TestAppendAllocs(t *testing.T, tests []AppendTest) {
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			quoted := td.Q.Quote(appendInput)
+			buf := make([]byte, 0, 1024)
+			if n := testing.AllocsPerRun(100, func() {
+				buf = td.Q.AppendQuote(buf[:0], appendInput)
+			}); n != 0 {
+				t.Errorf("%s.AppendQuote() allocs = %v; want 0", td.Name, n)
+			}
+			if n := testing.AllocsPerRun(100, func() {
+				buf, _ = td.Q.AppendUnquote(buf[:0], quoted)
+			}); n != 0 {
+				t.Errorf("%s.AppendUnquote() allocs = %v; want 0", td.Name, n)
+			}
+		})
+	}
+}
+
+func BenchmarkAppendQuote(b *testing.B, tests []AppendTest) {
+	for _, td := range tests {
+		b.Run(td.Name, func(b *testing.B) {
+			buf := make([]byte, 0, 1024)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf = td.Q.AppendQuote(buf[:0], appendInput)
+			}
+		})
+	}
+}
+
+func BenchmarkAppendUnquote(b *testing.B, tests []AppendTest) {
+	for _, td := range tests {
+		b.Run(td.Name, func(b *testing.B) {
+			quoted := td.Q.Quote(appendInput)
+			buf := make([]byte, 0, 1024)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf, _ = td.Q.AppendUnquote(buf[:0], quoted)
+			}
+		})
+	}
+}
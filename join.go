@@ -0,0 +1,92 @@
+package quote
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Policy controls which arguments are quoted by JoinPolicy.
+type Policy int
+
+const (
+	// QuoteNeeded quotes only arguments that must be quoted
+	// as reported by MustQuote, and empty arguments.
+	QuoteNeeded Policy = iota
+
+	// QuoteAlways quotes every argument.
+	QuoteAlways
+
+	// QuoteArgs quotes every argument except the first one (the program name),
+	// which is quoted only when needed as with QuoteNeeded.
+	QuoteArgs
+)
+
+// Join quotes args with q when needed and joins them with spaces
+// to form a single command line.
+//
+// It is equivalent to JoinPolicy(q, args, QuoteNeeded).
+func Join(q Quoting, args []string) string {
+	return JoinPolicy(q, args, QuoteNeeded)
+}
+
+// JoinPolicy quotes args with q according to p and joins them with spaces
+// to form a single command line.
+//
+// Empty arguments and arguments with spaces or tabs are always quoted
+// as otherwise they would disappear from the command line or be split.
+// Some quotings, like windows.Cmd, which only escapes characters special to cmd.exe,
+// can't represent an empty argument by themselves, so it still disappears:
+// use JoinStrict to get an error instead, or chain such a quoting
+// with one that can, like quote.Chain(windows.Argv, windows.Cmd).
+func JoinPolicy(q Quoting, args []string, p Policy) string {
+	s, _ := join(q, args, p, false)
+	return s
+}
+
+// JoinStrict is like JoinPolicy but returns an error of type *UnrepresentableError
+// if q can't represent an argument, as with Convert, or an empty argument.
+func JoinStrict(q Quoting, args []string, p Policy) (string, error) {
+	return join(q, args, p, true)
+}
+
+func join(q Quoting, args []string, p Policy, strict bool) (string, error) {
+	var buf strings.Builder
+	for i, arg := range args {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		if !mustQuote(q, arg, i, p) {
+			buf.WriteString(arg)
+			continue
+		}
+		if !strict {
+			buf.WriteString(q.Quote(arg))
+			continue
+		}
+		s, err := quoteExact(q, arg)
+		if err != nil {
+			return "", err
+		}
+		if s == "" {
+			e := &UnrepresentableError{Msg: "empty argument " + strconv.Itoa(i)}
+			if st, ok := q.(fmt.Stringer); ok {
+				e.Dialect = st.String()
+			}
+			return "", e
+		}
+		buf.WriteString(s)
+	}
+	return buf.String(), nil
+}
+
+func mustQuote(q Quoting, arg string, i int, p Policy) bool {
+	switch {
+	case arg == "", strings.ContainsAny(arg, " \t"):
+		return true
+	case p == QuoteAlways, p == QuoteArgs && i > 0:
+		return true
+	default:
+		return q.MustQuote(arg)
+	}
+}
@@ -0,0 +1,179 @@
+package quote_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleJoin() {
+	fmt.Println(quote.Join(unix.SingleQuote, []string{"cp", "-r", "My Documents", ""}))
+	fmt.Println(quote.JoinPolicy(windows.Argv, []string{"callme.exe", "/v", `C:\Program Files\`}, quote.QuoteArgs))
+	// Output:
+	// cp -r 'My Documents' ''
+	// callme.exe "/v" "C:\Program Files\\"
+}
+
+func TestJoinPolicy(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Q      quote.Quoting
+		Args   []string
+		Policy quote.Policy
+		Output string
+	}{
+		{
+			Name:   "no args",
+			Q:      unix.SingleQuote,
+			Args:   nil,
+			Policy: quote.QuoteNeeded,
+			Output: "",
+		},
+		{
+			Name:   "unix.SingleQuote;QuoteNeeded",
+			Q:      unix.SingleQuote,
+			Args:   []string{"echo", "a b", "", "c"},
+			Policy: quote.QuoteNeeded,
+			Output: "echo 'a b' '' c",
+		},
+		{
+			Name:   "unix.ANSIC;QuoteNeeded",
+			Q:      unix.ANSIC,
+			Args:   []string{"printf", "%s\n", "it's"},
+			Policy: quote.QuoteNeeded,
+			Output: `printf $'%s\n' $'it\'s'`,
+		},
+		{
+			Name:   "unix.SingleQuote;QuoteAlways",
+			Q:      unix.SingleQuote,
+			Args:   []string{"echo", "a"},
+			Policy: quote.QuoteAlways,
+			Output: "'echo' 'a'",
+		},
+		{
+			Name:   "windows.Argv;QuoteArgs",
+			Q:      windows.Argv,
+			Args:   []string{"callme.exe", "a", "b c"},
+			Policy: quote.QuoteArgs,
+			Output: `callme.exe "a" "b c"`,
+		},
+		{
+			Name:   "windows.Argv;QuoteArgs;unsafe program name",
+			Q:      windows.Argv,
+			Args:   []string{`C:\Program Files\callme.exe`, "a"},
+			Policy: quote.QuoteArgs,
+			Output: `"C:\Program Files\callme.exe" "a"`,
+		},
+		{
+			Name:   "windows.PSSingleQuote;QuoteNeeded",
+			Q:      windows.PSSingleQuote,
+			Args:   []string{"Write-Output", "it's", ""},
+			Policy: quote.QuoteNeeded,
+			Output: "Write-Output 'it''s' ''",
+		},
+		{
+			Name:   "windows.Msiexec;QuoteNeeded",
+			Q:      windows.Msiexec,
+			Args:   []string{"/i", `a "b"`},
+			Policy: quote.QuoteNeeded,
+			Output: `/i "a ""b"""`,
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			testutil.TestDiff(t, "JoinPolicy()", td.Output, quote.JoinPolicy(td.Q, td.Args, td.Policy))
+		})
+	}
+}
+
+func TestJoinPolicy_Cmd(t *testing.T) {
+	testutil.TestDiff(t, "JoinPolicy()", "dir", quote.JoinPolicy(windows.Cmd, []string{"dir"}, quote.QuoteNeeded))
+	testutil.TestDiff(t, "JoinPolicy()", "echo a^ b^\tc ", quote.JoinPolicy(windows.Cmd, []string{"echo", "a b\tc", ""}, quote.QuoteNeeded))
+	testutil.TestDiff(t, "Args.String()", "dir a^ b", quote.Args{Q: windows.Cmd, Args: []string{"dir", "a b"}}.String())
+}
+
+func TestJoinStrict(t *testing.T) {
+	s, err := quote.JoinStrict(unix.SingleQuote, []string{"echo", "a b", ""}, quote.QuoteNeeded)
+	if err != nil {
+		t.Fatalf("JoinStrict() = _, %v; want nil", err)
+	}
+	testutil.TestDiff(t, "JoinStrict()", "echo 'a b' ''", s)
+}
+
+func TestJoinStrict_ShouldFail(t *testing.T) {
+	tests := []struct {
+		Name string
+		Q    quote.Quoting
+		Args []string
+		Err  error
+	}{
+		{
+			Name: "empty argument",
+			Q:    windows.Cmd,
+			Args: []string{"echo", "a b", ""},
+			Err: &quote.UnrepresentableError{
+				Msg:     "empty argument 2",
+				Dialect: "windows.Cmd",
+			},
+		},
+		{
+			Name: "NUL",
+			Q:    unix.SingleQuote,
+			Args: []string{"echo", "a\x00"},
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "unix.SingleQuote",
+				Input:   "a\x00",
+				Offset:  1,
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			_, err := quote.JoinStrict(td.Q, td.Args, quote.QuoteNeeded)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Errorf("JoinStrict() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJoinPolicy_Chain(t *testing.T) {
+	q := quote.Chain(windows.Argv, windows.Cmd)
+	testutil.TestDiff(t, "JoinPolicy()", `echo ^"a^ b^" ^"^"`, quote.JoinPolicy(q, []string{"echo", "a b", ""}, quote.QuoteNeeded))
+}
+
+func TestJoin(t *testing.T) {
+	args := []string{"echo", "a b", ""}
+	testutil.TestDiff(t, "Join()", quote.JoinPolicy(unix.SingleQuote, args, quote.QuoteNeeded), quote.Join(unix.SingleQuote, args))
+}
+
+func TestJoin_Split(t *testing.T) {
+	args := []string{"callme", "", "a b", `"c"`, `'d'`, `e\\"f\`, "$g", "h\ti"}
+	for _, q := range []quote.Quoting{
+		unix.SingleQuote,
+		unix.DoubleQuote,
+		unix.ANSIC,
+		windows.Argv,
+		windows.Msiexec,
+		windows.PSSingleQuote,
+		windows.PSDoubleQuote,
+		windows.PwshDoubleQuote,
+	} {
+		for _, p := range []quote.Policy{quote.QuoteNeeded, quote.QuoteAlways, quote.QuoteArgs} {
+			cmdline := quote.JoinPolicy(q, args, p)
+			split, err := q.(quote.Splitter).Split(cmdline)
+			if err != nil {
+				t.Fatalf("Split(%q) = _, %v; want nil", cmdline, err)
+			}
+			if diff := cmp.Diff(args, split); diff != "" {
+				t.Errorf("Split(%q) mismatch (-want +got):\n%s", cmdline, diff)
+			}
+		}
+	}
+}
@@ -4,6 +4,12 @@
 // See the documentation for the unix and windows packages for more information.
 package quote
 
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
 // Quoting quotes and and unquotes textual command-line arguments and variables.
 type Quoting interface {
 	// MustQuote reports whether s must be quoted in order
@@ -32,10 +38,185 @@ type BinaryQuoting interface {
 	UnquoteBinary(s string) ([]byte, error)
 }
 
+// StrictQuoting quotes textual command-line arguments and variables,
+// failing when they can't be represented.
+type StrictQuoting interface {
+	Quoting
+
+	// QuoteStrict returns s quoted like Quote does
+	// or an error of type *UnrepresentableError if s contains a character
+	// that can't appear correctly as part of a single command-line argument or variable,
+	// like a NUL byte.
+	QuoteStrict(s string) (string, error)
+}
+
+// StrictBinaryQuoting quotes binary command-line arguments and variables,
+// failing when they can't be represented.
+type StrictBinaryQuoting interface {
+	BinaryQuoting
+
+	// QuoteBinaryStrict returns b quoted like QuoteBinary does
+	// or an error of type *UnrepresentableError if b contains a byte sequence
+	// that can't appear correctly as part of a single command-line argument or variable,
+	// like a NUL byte or, for dialects representing text only, an invalid UTF-8 sequence.
+	QuoteBinaryStrict(b []byte) (string, error)
+}
+
+// Appender appends quoted and unquoted textual command-line arguments and variables
+// to byte slices, avoiding allocations when they have enough capacity.
+type Appender interface {
+	Quoting
+
+	// AppendQuote appends to dst s quoted such that it appears correctly
+	// as a single command-line argument or variable,
+	// and returns the extended buffer.
+	AppendQuote(dst []byte, s string) []byte
+
+	// AppendUnquote interprets s as a quoted string, appends to dst
+	// the string value that s quotes, and returns the extended buffer.
+	// If s is not a valid quoted string, dst is returned unchanged.
+	AppendUnquote(dst []byte, s string) ([]byte, error)
+}
+
+// Splitter splits command lines into textual command-line arguments.
+type Splitter interface {
+	Quoting
+
+	// Split splits cmdline into arguments separated by unquoted whitespace,
+	// returning the string values that they quote.
+	// Unquoted characters inside arguments are taken literally.
+	Split(cmdline string) ([]string, error)
+}
+
+// Prefixer unquotes textual command-line arguments and variables
+// at the beginning of strings.
+type Prefixer interface {
+	Quoting
+
+	// QuotedPrefix interprets the longest quoted string at the beginning of s,
+	// returning the string value that it quotes and the rest of s.
+	QuotedPrefix(s string) (token, rest string, err error)
+}
+
+// ErrorKind is a kind of SyntaxError.
+// SyntaxError unwraps to its kind, so it can be tested with errors.Is:
+//
+//	if errors.Is(err, quote.ErrUnterminated) {
+//		// Ask for more input.
+//	}
+type ErrorKind int
+
+// Kinds of syntax errors.
+const (
+	ErrUnterminated    ErrorKind = iota + 1 // quoted string is not terminated or missing
+	ErrBadEscape                            // escape sequence is invalid or incomplete
+	ErrOutsideQuotes                        // character appears outside of quoted string
+	ErrUnsupportedChar                      // character can't appear unescaped in quoted string
+)
+
+var errorKinds = [...]string{
+	ErrUnterminated:    "unterminated quoted string",
+	ErrBadEscape:       "bad escape sequence",
+	ErrOutsideQuotes:   "character outside of quoted string",
+	ErrUnsupportedChar: "unsupported character",
+}
+
+func (k ErrorKind) Error() string {
+	if k > 0 && int(k) < len(errorKinds) {
+		return "quote: " + errorKinds[k]
+	}
+	return "quote: ErrorKind(" + strconv.Itoa(int(k)) + ")"
+}
+
 // SyntaxError represents an error during unquoting of the string.
 type SyntaxError struct {
-	Msg    string // description of error
-	Offset int    // error occurred after reading Offset bytes
+	Msg     string    // description of error
+	Kind    ErrorKind // kind of error
+	Dialect string    // name of the dialect, like "unix.SingleQuote", if known
+	Input   string    // string being unquoted, if known
+	Offset  int       // byte offset of the offending character or escape sequence, or length of the input if it ended prematurely
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Dialect != "" {
+		return e.Dialect + ": syntax error: " + e.Msg
+	}
+	return "syntax error: " + e.Msg
+}
+
+// Unwrap returns the kind of the error.
+func (e *SyntaxError) Unwrap() error {
+	if e.Kind == 0 {
+		return nil
+	}
+	return e.Kind
+}
+
+// hasOffset reports whether Input is known and Offset is within it.
+func (e *SyntaxError) hasOffset() bool {
+	return e.Input != "" && e.Offset >= 0 && e.Offset <= len(e.Input)
+}
+
+// RuneOffset returns the offset of the error in Input in runes
+// or -1 if Input is unknown or Offset is out of its range.
+func (e *SyntaxError) RuneOffset() int {
+	if !e.hasOffset() {
+		return -1
+	}
+	return utf8.RuneCountInString(e.Input[:e.Offset])
 }
 
-func (e *SyntaxError) Error() string { return "syntax error: " + e.Msg }
+// Column returns the offset of the error in runes from the start
+// of its line in Input or -1 if Input is unknown or Offset is out of its range.
+func (e *SyntaxError) Column() int {
+	if !e.hasOffset() {
+		return -1
+	}
+	return utf8.RuneCountInString(e.Input[strings.LastIndexByte(e.Input[:e.Offset], '\n')+1 : e.Offset])
+}
+
+// excerptRunes is the maximum number of runes around the error in excerpts.
+const excerptRunes = 32
+
+// Excerpt returns a part of the line of Input around the error
+// and the offset of the error in it in runes, so a caret can be put under the offending character.
+// If Input is unknown or Offset is out of its range, it returns "", -1.
+func (e *SyntaxError) Excerpt() (excerpt string, caret int) {
+	if !e.hasOffset() {
+		return "", -1
+	}
+	start := strings.LastIndexByte(e.Input[:e.Offset], '\n') + 1
+	end := len(e.Input)
+	if i := strings.IndexByte(e.Input[e.Offset:], '\n'); i >= 0 {
+		end = e.Offset + i
+	}
+	for caret = utf8.RuneCountInString(e.Input[start:e.Offset]); caret > excerptRunes; caret-- {
+		_, width := utf8.DecodeRuneInString(e.Input[start:])
+		start += width
+	}
+	for i, n := e.Offset, 0; i < end; n++ {
+		if n == excerptRunes {
+			end = i
+			break
+		}
+		_, width := utf8.DecodeRuneInString(e.Input[i:])
+		i += width
+	}
+	return e.Input[start:end], caret
+}
+
+// UnrepresentableError represents an error during quoting of the string
+// that contains a character which can't be represented.
+type UnrepresentableError struct {
+	Msg     string // description of error
+	Dialect string // name of the dialect, like "windows.Cmd"
+	Input   string // string being quoted
+	Offset  int    // byte offset of the offending character
+}
+
+func (e *UnrepresentableError) Error() string {
+	if e.Dialect != "" {
+		return e.Dialect + ": can't quote: " + e.Msg
+	}
+	return "can't quote: " + e.Msg
+}
@@ -0,0 +1,173 @@
+package quote_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleSyntaxError_Excerpt() {
+	_, err := unix.ANSIC.Unquote(`$'café \q \x'`)
+	var e *quote.SyntaxError
+	if errors.As(err, &e) {
+		excerpt, caret := e.Excerpt()
+		fmt.Println(err)
+		fmt.Println(excerpt)
+		fmt.Println(strings.Repeat(" ", caret) + "^")
+		fmt.Println(errors.Is(err, quote.ErrBadEscape))
+	}
+	// Output:
+	// unix.ANSIC: syntax error: unterminated escape sequence `\x`
+	// $'café \q \x'
+	//           ^
+	// true
+}
+
+func TestSyntaxError_Is(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Q     quote.Quoting
+		Input string
+		Kind  quote.ErrorKind
+	}{
+		{Name: "unix.SingleQuote", Q: unix.SingleQuote, Input: "'a", Kind: quote.ErrUnterminated},
+		{Name: "unix.DoubleQuote", Q: unix.DoubleQuote, Input: `"$"`, Kind: quote.ErrUnsupportedChar},
+		{Name: "unix.ANSIC", Q: unix.ANSIC, Input: `$'\c+'`, Kind: quote.ErrBadEscape},
+		{Name: "windows.Argv", Q: windows.Argv, Input: `"a"b`, Kind: quote.ErrOutsideQuotes},
+		{Name: "windows.PwshDoubleQuote", Q: windows.PwshDoubleQuote, Input: "\"`u{}\"", Kind: quote.ErrBadEscape},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			_, err := td.Q.Unquote(td.Input)
+			if !errors.Is(err, td.Kind) {
+				t.Errorf("errors.Is(%v, %v) = false; want true", err, td.Kind)
+			}
+			var e *quote.SyntaxError
+			if !errors.As(err, &e) {
+				t.Fatalf("errors.As(%v) = false; want true", err)
+			}
+			if e.Dialect != td.Name {
+				t.Errorf("SyntaxError.Dialect = %q; want %q", e.Dialect, td.Name)
+			}
+		})
+	}
+}
+
+func TestSyntaxError_RuneOffset_Column_Excerpt(t *testing.T) {
+	tests := []struct {
+		Name                      string
+		Err                       *quote.SyntaxError
+		RuneOffset, Column, Caret int
+		Excerpt                   string
+	}{
+		{
+			Name:       "start",
+			Err:        &quote.SyntaxError{Input: "abc", Offset: 0},
+			RuneOffset: 0,
+			Column:     0,
+			Excerpt:    "abc",
+			Caret:      0,
+		},
+		{
+			Name:       "end",
+			Err:        &quote.SyntaxError{Input: "abc", Offset: 3},
+			RuneOffset: 3,
+			Column:     3,
+			Excerpt:    "abc",
+			Caret:      3,
+		},
+		{
+			Name:       "multibyte",
+			Err:        &quote.SyntaxError{Input: "'жж'ж", Offset: 6},
+			RuneOffset: 4,
+			Column:     4,
+			Excerpt:    "'жж'ж",
+			Caret:      4,
+		},
+		{
+			Name:       "multiline",
+			Err:        &quote.SyntaxError{Input: "'a\nbc'd\ne'", Offset: 6},
+			RuneOffset: 6,
+			Column:     3,
+			Excerpt:    "bc'd",
+			Caret:      3,
+		},
+		{
+			Name:       "long line",
+			Err:        &quote.SyntaxError{Input: strings.Repeat("a", 50) + "!" + strings.Repeat("b", 50), Offset: 50},
+			RuneOffset: 50,
+			Column:     50,
+			Excerpt:    strings.Repeat("a", 32) + "!" + strings.Repeat("b", 31),
+			Caret:      32,
+		},
+		{
+			Name:       "unknown input",
+			Err:        &quote.SyntaxError{Offset: 10},
+			RuneOffset: -1,
+			Column:     -1,
+			Excerpt:    "",
+			Caret:      -1,
+		},
+		{
+			Name:       "unknown input;zero offset",
+			Err:        &quote.SyntaxError{Offset: 0},
+			RuneOffset: -1,
+			Column:     -1,
+			Excerpt:    "",
+			Caret:      -1,
+		},
+		{
+			Name:       "negative offset",
+			Err:        &quote.SyntaxError{Input: "abc", Offset: -1},
+			RuneOffset: -1,
+			Column:     -1,
+			Excerpt:    "",
+			Caret:      -1,
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			if n := td.Err.RuneOffset(); n != td.RuneOffset {
+				t.Errorf("RuneOffset() = %d; want %d", n, td.RuneOffset)
+			}
+			if n := td.Err.Column(); n != td.Column {
+				t.Errorf("Column() = %d; want %d", n, td.Column)
+			}
+			excerpt, caret := td.Err.Excerpt()
+			if excerpt != td.Excerpt || caret != td.Caret {
+				t.Errorf("Excerpt() = %q, %d; want %q, %d", excerpt, caret, td.Excerpt, td.Caret)
+			}
+		})
+	}
+}
+
+func TestUnrepresentableError_Error(t *testing.T) {
+	tests := []struct {
+		Name string
+		Err  *quote.UnrepresentableError
+		Msg  string
+	}{
+		{
+			Name: "dialect",
+			Err:  &quote.UnrepresentableError{Msg: "unsupported character U+0000", Dialect: "unix.SingleQuote"},
+			Msg:  "unix.SingleQuote: can't quote: unsupported character U+0000",
+		},
+		{
+			Name: "no dialect",
+			Err:  &quote.UnrepresentableError{Msg: "unsupported context comment"},
+			Msg:  "can't quote: unsupported context comment",
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			if msg := td.Err.Error(); msg != td.Msg {
+				t.Errorf("Error() = %q; want %q", msg, td.Msg)
+			}
+		})
+	}
+}
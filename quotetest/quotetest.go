@@ -0,0 +1,285 @@
+// Package quotetest implements support for testing implementations of quote.Quoting.
+package quotetest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+)
+
+// Input is a named test input.
+type Input struct {
+	Name, Input string
+}
+
+var baseInputs = []Input{
+	{
+		Name:  "ascii: symbols",
+		Input: "$%'()*+,-./<>:;=",
+	},
+	{
+		Name:  "unicode: emoji",
+		Input: "😇🤖💝🙇🏿‍♂️",
+	},
+	{
+		Name:  "unicode: text",
+		Input: "Testing «ταБЬℓσ»: 1<2 & 4+1>3, now 20% off!",
+	},
+}
+
+// Inputs returns test inputs containing runs of delim, the quote character of a dialect,
+// runs of blanks, quotes, backslashes and unsafeChars, the characters special to the dialect,
+// at the beginning, in the middle and at the end.
+func Inputs(delim byte, unsafeChars ...byte) []Input {
+	t := make([]Input, len(baseInputs))
+	copy(t, baseInputs)
+	for i := 1; i <= 8; i++ {
+		t = append(t, Input{
+			Name:  fmt.Sprintf("delim=%q;i=%d;beginning", delim, i),
+			Input: strings.Repeat(string(delim), i) + "bar",
+		})
+		t = append(t, Input{
+			Name:  fmt.Sprintf("delim=%q;i=%d;middle", delim, i),
+			Input: "foo" + strings.Repeat(string(delim), i) + "bar",
+		})
+		t = append(t, Input{
+			Name:  fmt.Sprintf("delim=%q;i=%d;end", delim, i),
+			Input: "foo" + strings.Repeat(string(delim), i),
+		})
+	}
+	for _, c := range append([]byte("\t\n \"'\\"), unsafeChars...) {
+		for i := 1; i <= 4; i++ {
+			cs := strings.Repeat(string(c), i)
+
+			t = append(t, Input{
+				Name:  fmt.Sprintf("cs=%q;beginning", cs),
+				Input: cs + "bar",
+			})
+			t = append(t, Input{
+				Name:  fmt.Sprintf("cs=%q;middle", cs),
+				Input: "foo" + cs + "bar",
+			})
+			t = append(t, Input{
+				Name:  fmt.Sprintf("cs=%q;end", cs),
+				Input: "foo" + cs,
+			})
+
+			ds := string(delim)
+			t = append(t, Input{
+				Name:  fmt.Sprintf("cs=%q;ds=%q;beginning", cs, ds),
+				Input: cs + ds + "bar",
+			})
+			t = append(t, Input{
+				Name:  fmt.Sprintf("cs=%q;ds=%q;middle", cs, ds),
+				Input: "foo" + cs + ds + "bar",
+			})
+			t = append(t, Input{
+				Name:  fmt.Sprintf("cs=%q;ds=%q;end", cs, ds),
+				Input: "foo" + cs + ds,
+			})
+
+			ds += string(delim)
+			t = append(t, Input{
+				Name:  fmt.Sprintf("cs=%q;ds=%q;beginning", cs, ds),
+				Input: cs + ds + "bar",
+			})
+			t = append(t, Input{
+				Name:  fmt.Sprintf("cs=%q;ds=%q;middle", cs, ds),
+				Input: "foo" + cs + ds + "bar",
+			})
+			t = append(t, Input{
+				Name:  fmt.Sprintf("cs=%q;ds=%q;end", cs, ds),
+				Input: "foo" + cs + ds,
+			})
+		}
+	}
+	return t
+}
+
+func init() {
+	b := make([]byte, 255)
+	for i := 1; i <= 255; i++ {
+		b[i-1] = byte(i)
+	}
+	baseInputs = append(baseInputs, Input{
+		Name:  "bytes: 1-255",
+		Input: string(b),
+	})
+}
+
+// Options configures TestQuoting.
+type Options struct {
+	// Delim is the quote character of the dialect.
+	Delim byte
+
+	// UnsafeChars are the characters special to the dialect.
+	// MustQuote must report true for any input containing one of them.
+	UnsafeChars []byte
+
+	// Inputs are additional inputs to test with.
+	Inputs []Input
+
+	// Malformed are strings Unquote must reject.
+	Malformed []string
+
+	// Exec, if not nil, passes quoted as a single argument to a real interpreter
+	// and returns the argument value the interpreter received.
+	Exec func(quoted string) (string, error)
+}
+
+// TestQuoting tests a quote.Quoting implementation.
+// It generates inputs with Inputs(opts.Delim, opts.UnsafeChars...), adds opts.Inputs,
+// and checks that:
+//
+//   - Unquote(Quote(s)) == s for every input s, or, if q implements quote.StrictQuoting,
+//     that QuoteStrict either does the same or returns a *quote.UnrepresentableError;
+//   - MustQuote(s) reports true if s contains a byte from opts.UnsafeChars
+//     and Quote(s) != s if MustQuote(s) reports true;
+//   - Unquote returns a *quote.SyntaxError with a non-zero Kind, a matching Input,
+//     a valid Offset and, if q implements fmt.Stringer, a matching Dialect
+//     for every string in opts.Malformed and every truncated quoted input it rejects;
+//   - quote.Appender methods, if implemented, agree with Quote and Unquote;
+//   - opts.Exec, if not nil, receives every quoted input unchanged.
+func TestQuoting(t *testing.T, q quote.Quoting, opts Options) {
+	t.Helper()
+	inputs := append(Inputs(opts.Delim, opts.UnsafeChars...), opts.Inputs...)
+	type quotedInput struct {
+		Name, Input, quoted string
+	}
+	var quoted []quotedInput
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, it := range inputs {
+			s, ok := quoteInput(t, q, it)
+			if !ok {
+				continue
+			}
+			quoted = append(quoted, quotedInput{Name: it.Name, Input: it.Input, quoted: s})
+			unquoted, err := q.Unquote(s)
+			if err != nil {
+				t.Errorf("%s: Unquote(%q) = _, %v; want nil", it.Name, s, err)
+			} else if unquoted != it.Input {
+				t.Errorf("%s: Unquote(%q) = %q; want %q", it.Name, s, unquoted, it.Input)
+			}
+			if a, ok := q.(quote.Appender); ok {
+				testAppender(t, a, it, s)
+			}
+		}
+	})
+	t.Run("MustQuote", func(t *testing.T) {
+		for _, it := range inputs {
+			must := q.MustQuote(it.Input)
+			if !must && strings.ContainsAny(it.Input, string(opts.UnsafeChars)) {
+				t.Errorf("%s: MustQuote(%q) = false; want true", it.Name, it.Input)
+			}
+			if must && q.Quote(it.Input) == it.Input {
+				t.Errorf("%s: MustQuote(%q) = true but Quote() returns it unchanged", it.Name, it.Input)
+			}
+		}
+	})
+	t.Run("SyntaxError", func(t *testing.T) {
+		for _, s := range opts.Malformed {
+			if _, err := q.Unquote(s); err == nil {
+				t.Errorf("Unquote(%q) = _, nil; want error", s)
+			} else {
+				testSyntaxError(t, q, s, err)
+			}
+		}
+		for _, it := range quoted {
+			for i := 0; i < len(it.quoted); i++ {
+				s := it.quoted[:i]
+				if _, err := q.Unquote(s); err != nil {
+					testSyntaxError(t, q, s, err)
+				}
+			}
+		}
+	})
+	if opts.Exec == nil {
+		return
+	}
+	t.Run("Exec", func(t *testing.T) {
+		for _, it := range quoted {
+			it := it
+			t.Run(it.Name, func(t *testing.T) {
+				t.Parallel()
+				out, err := opts.Exec(it.quoted)
+				if err != nil {
+					t.Fatalf("Exec(%q) = _, %v; want nil", it.quoted, err)
+				}
+				if out != it.Input {
+					t.Errorf("Exec(%q) = %q; want %q", it.quoted, out, it.Input)
+				}
+			})
+		}
+	})
+}
+
+// quoteInput returns it quoted with q, reporting false if q can't represent it.
+func quoteInput(t *testing.T, q quote.Quoting, it Input) (string, bool) {
+	t.Helper()
+	s := q.Quote(it.Input)
+	sq, ok := q.(quote.StrictQuoting)
+	if !ok {
+		return s, true
+	}
+	strict, err := sq.QuoteStrict(it.Input)
+	if err != nil {
+		var e *quote.UnrepresentableError
+		if !errors.As(err, &e) {
+			t.Errorf("%s: QuoteStrict(%q) = _, %v; want *quote.UnrepresentableError", it.Name, it.Input, err)
+		}
+		return "", false
+	}
+	if strict != s {
+		t.Errorf("%s: QuoteStrict(%q) = %q; want %q", it.Name, it.Input, strict, s)
+	}
+	return s, true
+}
+
+func testAppender(t *testing.T, a quote.Appender, it Input, quoted string) {
+	t.Helper()
+	const prefix = "prefix"
+	if b := a.AppendQuote([]byte(prefix), it.Input); string(b) != prefix+quoted {
+		t.Errorf("%s: AppendQuote(%q, %q) = %q; want %q", it.Name, prefix, it.Input, b, prefix+quoted)
+	}
+	if b, err := a.AppendUnquote([]byte(prefix), quoted); err != nil {
+		t.Errorf("%s: AppendUnquote(%q, %q) = _, %v; want nil", it.Name, prefix, quoted, err)
+	} else if string(b) != prefix+it.Input {
+		t.Errorf("%s: AppendUnquote(%q, %q) = %q; want %q", it.Name, prefix, quoted, b, prefix+it.Input)
+	}
+}
+
+func testSyntaxError(t *testing.T, q quote.Quoting, s string, err error) {
+	t.Helper()
+	var e *quote.SyntaxError
+	if !errors.As(err, &e) {
+		t.Errorf("Unquote(%q) = _, %v; want *quote.SyntaxError", s, err)
+		return
+	}
+	if e.Kind == 0 {
+		t.Errorf("Unquote(%q) = _, %v; want non-zero SyntaxError.Kind", s, err)
+	} else if !errors.Is(err, e.Kind) {
+		t.Errorf("errors.Is(%v, %v) = false; want true", err, e.Kind)
+	}
+	if e.Input != s {
+		t.Errorf("Unquote(%q) = _, %v; SyntaxError.Input = %q; want %q", s, err, e.Input, s)
+	}
+	if e.Offset < 0 || e.Offset > len(s) {
+		t.Errorf("Unquote(%q) = _, %v; SyntaxError.Offset = %d; want [0, %d]", s, err, e.Offset, len(s))
+	}
+	if name, ok := q.(fmt.Stringer); ok && e.Dialect != name.String() {
+		t.Errorf("Unquote(%q) = _, %v; SyntaxError.Dialect = %q; want %q", s, err, e.Dialect, name.String())
+	}
+	if a, ok := q.(quote.Appender); ok {
+		const prefix = "prefix"
+		b, err2 := a.AppendUnquote([]byte(prefix), s)
+		if err2 == nil || err2.Error() != err.Error() {
+			t.Errorf("AppendUnquote(%q, %q) = _, %v; want %v", prefix, s, err2, err)
+		}
+		if string(b) != prefix {
+			t.Errorf("AppendUnquote(%q, %q) = %q, _; want %q", prefix, s, b, prefix)
+		}
+	}
+}
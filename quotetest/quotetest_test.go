@@ -0,0 +1,133 @@
+package quotetest_test
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/quotetest"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func TestTestQuoting(t *testing.T) {
+	tests := []struct {
+		Name string
+		Q    quote.Quoting
+		Opts quotetest.Options
+	}{
+		{
+			Name: "unix.SingleQuote",
+			Q:    unix.SingleQuote,
+			Opts: quotetest.Options{
+				Delim:       '\'',
+				UnsafeChars: []byte("\t\n \""),
+				Malformed:   []string{"'a", `a'b`},
+				Exec:        shExec,
+			},
+		},
+		{
+			Name: "unix.DoubleQuote",
+			Q:    unix.DoubleQuote,
+			Opts: quotetest.Options{
+				Delim:       '"',
+				UnsafeChars: []byte("\t\n $'"),
+				Malformed:   []string{`"a`, `"$"`},
+				Exec:        shExec,
+			},
+		},
+		{
+			Name: "unix.ANSIC",
+			Q:    unix.ANSIC,
+			Opts: quotetest.Options{
+				Delim:       '\'',
+				UnsafeChars: []byte("\t\n $\""),
+				Malformed:   []string{`$'a`, `$'\c+'`, `$'\x'`},
+				Exec:        ansiCExec,
+			},
+		},
+		{
+			Name: "windows.Argv",
+			Q:    windows.Argv,
+			Opts: quotetest.Options{
+				Delim:     '"',
+				Malformed: []string{`"a`, `"a"b`},
+			},
+		},
+		{
+			Name: "windows.Cmd",
+			Q:    windows.Cmd,
+			Opts: quotetest.Options{Delim: '"'},
+		},
+		{
+			Name: "windows.Msiexec",
+			Q:    windows.Msiexec,
+			Opts: quotetest.Options{
+				Delim:     '"',
+				Malformed: []string{`"a`},
+			},
+		},
+		{
+			Name: "windows.PSSingleQuote",
+			Q:    windows.PSSingleQuote,
+			Opts: quotetest.Options{
+				Delim:       '\'',
+				UnsafeChars: []byte("$`"),
+				Malformed:   []string{"'a"},
+			},
+		},
+		{
+			Name: "windows.PSDoubleQuote",
+			Q:    windows.PSDoubleQuote,
+			Opts: quotetest.Options{
+				Delim:       '"',
+				UnsafeChars: []byte("$`"),
+				Malformed:   []string{`"a`},
+			},
+		},
+		{
+			Name: "windows.PwshDoubleQuote",
+			Q:    windows.PwshDoubleQuote,
+			Opts: quotetest.Options{
+				Delim:       '"',
+				UnsafeChars: []byte("$`"),
+				Malformed:   []string{`"a`, "\"`u{}\""},
+			},
+		},
+	}
+	for _, td := range tests {
+		if runtime.GOOS == "windows" {
+			td.Opts.Exec = nil
+		}
+		if td.Q == unix.ANSIC && ansiCShell == "" {
+			td.Opts.Exec = nil
+		}
+		t.Run(td.Name, func(t *testing.T) {
+			quotetest.TestQuoting(t, td.Q, td.Opts)
+		})
+	}
+}
+
+func shExec(quoted string) (string, error) {
+	out, _, err := testutil.Output("sh", "-c", `printf '%s\n' `+quoted)
+	return string(out), err
+}
+
+// ansiCShell is a shell supporting \uxxxx escapes in ANSI-C quoted strings, if any.
+var ansiCShell = func() string {
+	for _, s := range []string{"bash", "zsh"} {
+		out, err := exec.Command(s, "-c", `printf '%s\n' $'\u200D'`).Output()
+		if err == nil && !bytes.Contains(out, []byte("$")) && !bytes.Contains(out, []byte(`200D`)) {
+			return s
+		}
+	}
+	return ""
+}()
+
+func ansiCExec(quoted string) (string, error) {
+	out, _, err := testutil.Output(ansiCShell, "-c", `printf '%s\n' `+quoted)
+	return string(out), err
+}
@@ -0,0 +1,117 @@
+package quote
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultPlaceholder is the placeholder used by Renderer if none is set.
+const DefaultPlaceholder = "REDACTED"
+
+// Renderer renders command lines like JoinPolicy, replacing secrets,
+// like passwords and tokens, with a placeholder.
+//
+// Secrets are replaced before quoting, so the rendered command line
+// is still valid and can be pasted, only with the placeholder in place of secrets.
+type Renderer struct {
+	Q           Quoting
+	Policy      Policy
+	Placeholder string // replaces secrets, DefaultPlaceholder if empty
+
+	// Flags are names of flags, like "--password" or "-p", whose values are secret.
+	// Both "--password=value" and "--password value" forms are redacted.
+	Flags []string
+
+	// Keys are names of properties, like "PASSWORD", whose values are secret
+	// in "PASSWORD=value" arguments. Keys are matched case-insensitively.
+	Keys []string
+
+	// Patterns match secrets anywhere in arguments.
+	// If a pattern has a parenthesized subexpression,
+	// only the text matched by the first one is replaced.
+	Patterns []*regexp.Regexp
+}
+
+// Render redacts args and quotes them with r.Q according to r.Policy
+// joining them with spaces to form a single command line.
+func (r *Renderer) Render(args []string) string {
+	return JoinPolicy(r.Q, r.Redact(args), r.Policy)
+}
+
+// Redact returns a copy of args with secrets replaced with the placeholder.
+func (r *Renderer) Redact(args []string) []string {
+	redacted := make([]string, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if r.isFlag(arg) && i+1 < len(args) {
+			redacted[i] = r.redactPatterns(arg)
+			i++
+			redacted[i] = r.placeholder(args[i])
+			continue
+		}
+		if name, value, ok := strings.Cut(arg, "="); ok && (r.isFlag(name) || r.isKey(name)) {
+			arg = name + "=" + r.placeholder(value)
+		}
+		redacted[i] = r.redactPatterns(arg)
+	}
+	return redacted
+}
+
+func (r *Renderer) isFlag(s string) bool {
+	for _, flag := range r.Flags {
+		if s == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Renderer) isKey(s string) bool {
+	for _, key := range r.Keys {
+		if strings.EqualFold(s, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholder returns the placeholder replacing secret,
+// or the empty string if secret is empty.
+func (r *Renderer) placeholder(secret string) string {
+	switch {
+	case secret == "":
+		return ""
+	case r.Placeholder == "":
+		return DefaultPlaceholder
+	default:
+		return r.Placeholder
+	}
+}
+
+func (r *Renderer) redactPatterns(s string) string {
+	for _, re := range r.Patterns {
+		matches := re.FindAllStringSubmatchIndex(s, -1)
+		if matches == nil {
+			continue
+		}
+		var (
+			buf  strings.Builder
+			last int
+		)
+		for _, m := range matches {
+			start, end := m[0], m[1]
+			if len(m) > 2 {
+				start, end = m[2], m[3]
+			}
+			if start < last || start == end {
+				continue
+			}
+			buf.WriteString(s[last:start])
+			buf.WriteString(r.placeholder(s[start:end]))
+			last = end
+		}
+		buf.WriteString(s[last:])
+		s = buf.String()
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package quote_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleRenderer() {
+	r := &quote.Renderer{
+		Q:     windows.Argv,
+		Flags: []string{"/password"},
+		Keys:  []string{"PASSWORD"},
+	}
+	fmt.Println(r.Render([]string{"msiexec", "/i", "app.msi", "PASSWORD=p@ss word", "/password", "hunter2"}))
+	// Output:
+	// msiexec /i app.msi PASSWORD=REDACTED /password REDACTED
+}
+
+func TestRenderer_Render(t *testing.T) {
+	tests := []struct {
+		Name   string
+		R      quote.Renderer
+		Args   []string
+		Output string
+	}{
+		{
+			Name:   "flag with value",
+			R:      quote.Renderer{Q: unix.SingleQuote, Flags: []string{"--password"}},
+			Args:   []string{"mysql", "--password=it's secret", "--user=root"},
+			Output: "mysql '--password=REDACTED' '--user=root'",
+		},
+		{
+			Name:   "flag followed by value",
+			R:      quote.Renderer{Q: unix.SingleQuote, Flags: []string{"-p"}},
+			Args:   []string{"mysql", "-p", "it's secret", "-p"},
+			Output: "mysql -p REDACTED -p",
+		},
+		{
+			Name:   "key",
+			R:      quote.Renderer{Q: windows.Argv, Keys: []string{"Password"}},
+			Args:   []string{"msiexec", `PASSWORD="a b"`, "USER=me", "password="},
+			Output: "msiexec PASSWORD=REDACTED USER=me password=",
+		},
+		{
+			Name: "pattern",
+			R: quote.Renderer{
+				Q:           unix.SingleQuote,
+				Placeholder: "***",
+				Patterns:    []*regexp.Regexp{regexp.MustCompile(`ghp_\w+`)},
+			},
+			Args:   []string{"git", "clone", "https://ghp_abc123@github.com/a/b"},
+			Output: "git clone 'https://***@github.com/a/b'",
+		},
+		{
+			Name: "pattern with subexpression",
+			R: quote.Renderer{
+				Q:        unix.SingleQuote,
+				Policy:   quote.QuoteArgs,
+				Patterns: []*regexp.Regexp{regexp.MustCompile(`Bearer (\S+)`)},
+			},
+			Args:   []string{"curl", "-H", "Authorization: Bearer abc", "-H", "X: Bearer"},
+			Output: "curl '-H' 'Authorization: Bearer REDACTED' '-H' 'X: Bearer'",
+		},
+		{
+			Name:   "no rules",
+			R:      quote.Renderer{Q: unix.SingleQuote},
+			Args:   []string{"echo", "--password=a"},
+			Output: "echo '--password=a'",
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			testutil.TestDiff(t, "Render()", td.Output, td.R.Render(td.Args))
+		})
+	}
+}
+
+func TestRenderer_Redact(t *testing.T) {
+	r := &quote.Renderer{Flags: []string{"--token"}}
+	args := []string{"app", "--token", "abc"}
+	redacted := r.Redact(args)
+	if diff := cmp.Diff([]string{"app", "--token", "REDACTED"}, redacted); diff != "" {
+		t.Errorf("Redact() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"app", "--token", "abc"}, args); diff != "" {
+		t.Errorf("Redact() modified args (-want +got):\n%s", diff)
+	}
+}
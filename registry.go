@@ -0,0 +1,69 @@
+package quote
+
+import (
+	"sort"
+	"sync"
+)
+
+// Dialect is a named Quoting along with the description of values it can represent.
+type Dialect struct {
+	Name    string
+	Quoting Quoting
+
+	NUL         bool // NUL bytes can be represented
+	Newline     bool // newline characters can be represented
+	Binary      bool // Quoting implements BinaryQuoting and StrictBinaryQuoting
+	InvalidUTF8 bool // invalid UTF-8 sequences can be represented by Quote
+}
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = make(map[string]Dialect)
+)
+
+// Register makes a dialect available by its name.
+// If Register is called twice with the same name or if the dialect has no name or Quoting,
+// it panics.
+//
+// The unix and windows packages register their quotings under their qualified names,
+// like "unix.SingleQuote" and "windows.Argv", as well as under the names of shells
+// and programs using them, like "bash" and "pwsh". Import them, even for side effects only,
+// to make them available:
+//
+//	import _ "github.com/sergeymakinen/go-quote/unix"
+func Register(d Dialect) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	if d.Name == "" {
+		panic("quote: Register dialect name is empty")
+	}
+	if d.Quoting == nil {
+		panic("quote: Register dialect " + d.Name + " Quoting is nil")
+	}
+	if _, dup := dialects[d.Name]; dup {
+		panic("quote: Register called twice for dialect " + d.Name)
+	}
+	dialects[d.Name] = d
+}
+
+// Lookup returns a dialect registered with the name.
+func Lookup(name string) (Dialect, bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	d, ok := dialects[name]
+	return d, ok
+}
+
+// Dialects returns all registered dialects sorted by name.
+func Dialects() []Dialect {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	ds := make([]Dialect, 0, len(dialects))
+	for _, d := range dialects {
+		ds = append(ds, d)
+	}
+	sort.Slice(ds, func(i, j int) bool {
+		return ds[i].Name < ds[j].Name
+	})
+	return ds
+}
@@ -0,0 +1,105 @@
+package quote_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleLookup() {
+	d, ok := quote.Lookup("pwsh")
+	if !ok {
+		return
+	}
+	fmt.Println(d.Quoting.Quote("a\tb"), d.NUL, d.Binary)
+	// Output:
+	// "a`tb" true true
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Quoting quote.Quoting
+	}{
+		{Name: "unix.SingleQuote", Quoting: unix.SingleQuote},
+		{Name: "unix.DoubleQuote", Quoting: unix.DoubleQuote},
+		{Name: "unix.ANSIC", Quoting: unix.ANSIC},
+		{Name: "windows.Argv", Quoting: windows.Argv},
+		{Name: "windows.Cmd", Quoting: windows.Cmd},
+		{Name: "windows.Msiexec", Quoting: windows.Msiexec},
+		{Name: "windows.PSSingleQuote", Quoting: windows.PSSingleQuote},
+		{Name: "windows.PSDoubleQuote", Quoting: windows.PSDoubleQuote},
+		{Name: "windows.PwshDoubleQuote", Quoting: windows.PwshDoubleQuote},
+		{Name: "sh", Quoting: unix.SingleQuote},
+		{Name: "bash", Quoting: unix.ANSIC},
+		{Name: "zsh", Quoting: unix.ANSIC},
+		{Name: "cmd", Quoting: windows.Cmd},
+		{Name: "powershell", Quoting: windows.PSDoubleQuote},
+		{Name: "pwsh", Quoting: windows.PwshDoubleQuote},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			d, ok := quote.Lookup(td.Name)
+			if !ok {
+				t.Fatalf("Lookup(%q) = _, false; want true", td.Name)
+			}
+			if d.Name != td.Name {
+				t.Errorf("Lookup(%q).Name = %q; want %q", td.Name, d.Name, td.Name)
+			}
+			if d.Quoting != td.Quoting {
+				t.Errorf("Lookup(%q).Quoting = %T; want %T", td.Name, d.Quoting, td.Quoting)
+			}
+			if _, ok := d.Quoting.(quote.BinaryQuoting); ok != d.Binary {
+				t.Errorf("Lookup(%q).Binary = %v; want %v", td.Name, d.Binary, ok)
+			}
+		})
+	}
+	if _, ok := quote.Lookup("unknown"); ok {
+		t.Error("Lookup(\"unknown\") = _, true; want false")
+	}
+}
+
+func TestDialects(t *testing.T) {
+	ds := quote.Dialects()
+	if len(ds) == 0 {
+		t.Fatal("Dialects() is empty")
+	}
+	for i := 1; i < len(ds); i++ {
+		if ds[i-1].Name >= ds[i].Name {
+			t.Errorf("Dialects() is not sorted: %q >= %q", ds[i-1].Name, ds[i].Name)
+		}
+	}
+}
+
+func TestRegister_ShouldPanic(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Dialect quote.Dialect
+	}{
+		{
+			Name:    "empty name",
+			Dialect: quote.Dialect{Quoting: unix.SingleQuote},
+		},
+		{
+			Name:    "nil quoting",
+			Dialect: quote.Dialect{Name: "test"},
+		},
+		{
+			Name:    "duplicate",
+			Dialect: quote.Dialect{Name: "unix.SingleQuote", Quoting: unix.SingleQuote},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Register() didn't panic")
+				}
+			}()
+			quote.Register(td.Dialect)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package quote
+
+// Shortest quotes s with each of candidates and returns the shortest result
+// along with the Quoting that produced it.
+// Ties are resolved in favor of the earlier candidate.
+//
+// A candidate is skipped if it can't represent s: if it implements StrictQuoting
+// and QuoteStrict fails, or if unquoting the result doesn't reproduce s exactly.
+// If no candidate can represent s, Shortest returns "", nil.
+func Shortest(s string, candidates ...Quoting) (string, Quoting) {
+	var (
+		shortest string
+		best     Quoting
+	)
+	for _, q := range candidates {
+		quoted, err := quoteExact(q, s)
+		if err == nil && (best == nil || len(quoted) < len(shortest)) {
+			shortest, best = quoted, q
+		}
+	}
+	return shortest, best
+}
@@ -0,0 +1,70 @@
+package quote_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleShortest() {
+	for _, s := range []string{"it's", `say "hi"`, "don't `run` $HOME"} {
+		quoted, _ := quote.Shortest(s, unix.SingleQuote, unix.DoubleQuote, unix.ANSIC)
+		fmt.Println(quoted)
+	}
+	// Output:
+	// "it's"
+	// 'say "hi"'
+	// $'don\'t `run` $HOME'
+}
+
+func TestShortest(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Input      string
+		Candidates []quote.Quoting
+		Output     string
+		Q          quote.Quoting
+	}{
+		{
+			Name:       "tie",
+			Input:      "abc",
+			Candidates: []quote.Quoting{unix.DoubleQuote, unix.SingleQuote},
+			Output:     `"abc"`,
+			Q:          unix.DoubleQuote,
+		},
+		{
+			Name:       "unrepresentable",
+			Input:      "a\x00b",
+			Candidates: []quote.Quoting{unix.SingleQuote, windows.PSSingleQuote, windows.PSDoubleQuote},
+			Output:     "\"a`0b\"",
+			Q:          windows.PSDoubleQuote,
+		},
+		{
+			Name:       "no round trip",
+			Input:      "a\xFFb",
+			Candidates: []quote.Quoting{lossyQuoting{unix.SingleQuote}},
+			Output:     "",
+			Q:          nil,
+		},
+		{
+			Name:       "no candidates",
+			Input:      "a\x00b",
+			Candidates: []quote.Quoting{unix.SingleQuote, windows.Argv},
+			Output:     "",
+			Q:          nil,
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			quoted, q := quote.Shortest(td.Input, td.Candidates...)
+			testutil.TestDiff(t, "Shortest()", td.Output, quoted)
+			if q != td.Q {
+				t.Errorf("Shortest() = _, %v; want %v", q, td.Q)
+			}
+		})
+	}
+}
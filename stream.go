@@ -0,0 +1,219 @@
+package quote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Transformer quotes or unquotes a string piece by piece.
+type Transformer interface {
+	// Transform appends to dst the result of transforming src
+	// and returns the extended buffer and the number of bytes of src consumed.
+	// If atEOF is false, Transform may leave a short trailing part of src unconsumed
+	// when it can't be transformed without more input; it must be passed again
+	// with the following input.
+	// atEOF must be true for the last piece of input, after which the Transformer
+	// must not be used anymore.
+	Transform(dst []byte, src string, atEOF bool) ([]byte, int, error)
+}
+
+// StreamQuoting quotes and unquotes textual command-line arguments and variables
+// piece by piece, without holding them in memory.
+type StreamQuoting interface {
+	Quoting
+
+	// NewQuoter returns a Transformer that quotes a string
+	// such that it appears correctly as a single command-line argument or variable.
+	NewQuoter() Transformer
+
+	// NewUnquoter returns a Transformer that interprets a string as a quoted string,
+	// returning the string value that it quotes.
+	NewUnquoter() Transformer
+}
+
+// quoteAll is a Transformer holding the whole input in memory
+// to quote it with a Quoting which doesn't implement StreamQuoting.
+type quoteAll struct {
+	q   Quoting
+	buf []byte
+}
+
+func (t *quoteAll) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	t.buf = append(t.buf, src...)
+	if !atEOF {
+		return dst, len(src), nil
+	}
+	return append(dst, t.q.Quote(string(t.buf))...), len(src), nil
+}
+
+// unquoteAll is a Transformer leaving the whole input unconsumed until its end
+// to unquote it with a Quoting which doesn't implement StreamQuoting,
+// so offsets of syntax errors are counted from the start of the input.
+type unquoteAll struct {
+	q Quoting
+}
+
+func (t *unquoteAll) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	if !atEOF {
+		return dst, 0, nil
+	}
+	s, err := t.q.Unquote(src)
+	if err != nil {
+		return nil, 0, err
+	}
+	return append(dst, s...), len(src), nil
+}
+
+var errClosed = errors.New("quote: write to closed Encoder")
+
+// Encoder quotes data written to it and writes the result to an underlying writer.
+type Encoder struct {
+	w   io.Writer
+	t   Transformer
+	src []byte // input that was not consumed by t yet
+	dst []byte
+	err error
+}
+
+// NewEncoder returns a new Encoder that quotes with q and writes to w.
+//
+// If q implements StreamQuoting, data is quoted as it's written.
+// Otherwise the whole data is held in memory and quoted on Close.
+func NewEncoder(w io.Writer, q Quoting) *Encoder {
+	e := &Encoder{w: w}
+	if sq, ok := q.(StreamQuoting); ok {
+		e.t = sq.NewQuoter()
+	} else {
+		e.t = &quoteAll{q: q}
+	}
+	return e
+}
+
+// Write quotes p and writes the result to the underlying writer.
+func (e *Encoder) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	e.src = append(e.src, p...)
+	if err := e.transform(false); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close finishes quoting and writes the rest of the result, such as a closing quote,
+// to the underlying writer. It doesn't close the underlying writer.
+func (e *Encoder) Close() error {
+	if e.err != nil {
+		if e.err == errClosed {
+			return nil
+		}
+		return e.err
+	}
+	if err := e.transform(true); err != nil {
+		return err
+	}
+	e.err = errClosed
+	return nil
+}
+
+func (e *Encoder) transform(atEOF bool) error {
+	var n int
+	e.dst, n, e.err = e.t.Transform(e.dst[:0], string(e.src), atEOF)
+	if e.err != nil {
+		return e.err
+	}
+	e.src = e.src[:copy(e.src, e.src[n:])]
+	if len(e.dst) > 0 {
+		_, e.err = e.w.Write(e.dst)
+	}
+	return e.err
+}
+
+const decoderBufSize = 4096
+
+// Decoder reads quoted data from an underlying reader and unquotes it.
+type Decoder struct {
+	r      io.Reader
+	t      Transformer
+	src    []byte // input that was not consumed by t yet
+	buf    []byte
+	dst    []byte // unquoted data that was not read yet
+	offset int    // number of bytes of input consumed by t
+	name   string // name of the dialect, if known
+	err    error
+}
+
+// NewDecoder returns a new Decoder that reads from r and unquotes with q.
+//
+// If q implements StreamQuoting, data is unquoted as it's read.
+// Otherwise the whole data is read into memory first.
+//
+// Offsets of errors of type *SyntaxError returned by Read are counted
+// from the start of r and their Input is empty.
+// If q implements fmt.Stringer, it names their Dialect.
+func NewDecoder(r io.Reader, q Quoting) *Decoder {
+	d := &Decoder{r: r}
+	if s, ok := q.(fmt.Stringer); ok {
+		d.name = s.String()
+	}
+	if sq, ok := q.(StreamQuoting); ok {
+		d.t = sq.NewUnquoter()
+	} else {
+		d.t = &unquoteAll{q: q}
+	}
+	return d
+}
+
+// Read reads up to len(p) bytes of unquoted data into p.
+func (d *Decoder) Read(p []byte) (int, error) {
+	for len(d.dst) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		d.fill()
+	}
+	n := copy(p, d.dst)
+	d.dst = d.dst[n:]
+	return n, nil
+}
+
+func (d *Decoder) fill() {
+	if cap(d.src)-len(d.src) < decoderBufSize {
+		// Grow geometrically, as transformers may leave the whole input unconsumed.
+		src := make([]byte, len(d.src), 2*len(d.src)+decoderBufSize)
+		copy(src, d.src)
+		d.src = src
+	}
+	n, err := d.r.Read(d.src[len(d.src):cap(d.src)])
+	d.src = d.src[:len(d.src)+n]
+	if err != nil && err != io.EOF {
+		d.err = err
+		return
+	}
+	atEOF := err == io.EOF
+	if n == 0 && !atEOF {
+		return
+	}
+	var terr error
+	d.buf, n, terr = d.t.Transform(d.buf[:0], string(d.src), atEOF)
+	if terr != nil {
+		var e *SyntaxError
+		if errors.As(terr, &e) {
+			e.Offset += d.offset
+			e.Input = ""
+			if e.Dialect == "" {
+				e.Dialect = d.name
+			}
+		}
+		d.err = terr
+		return
+	}
+	d.offset += n
+	d.src = d.src[:copy(d.src, d.src[n:])]
+	d.dst = d.buf
+	if atEOF {
+		d.err = io.EOF
+	}
+}
@@ -0,0 +1,184 @@
+package quote_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/unix"
+	"github.com/sergeymakinen/go-quote/windows"
+)
+
+func ExampleNewEncoder() {
+	e := quote.NewEncoder(os.Stdout, unix.SingleQuote)
+	io.Copy(e, strings.NewReader("echo 'Hello, World!'"))
+	e.Close()
+	fmt.Println()
+	// Output:
+	// 'echo '"'"'Hello, World!'"'"''
+}
+
+func ExampleNewDecoder() {
+	d := quote.NewDecoder(strings.NewReader(`"a b:\"c d\" 'e''f'  \"g\\\"\""`), windows.Argv)
+	io.Copy(os.Stdout, d)
+	fmt.Println()
+	// Output:
+	// a b:"c d" 'e''f'  "g\""
+}
+
+var streamQuotings = []struct {
+	Name string
+	Q    quote.Quoting
+}{
+	{Name: "unix.SingleQuote", Q: unix.SingleQuote},
+	{Name: "unix.DoubleQuote", Q: unix.DoubleQuote},
+	{Name: "unix.ANSIC", Q: unix.ANSIC},
+	{Name: "windows.Argv", Q: windows.Argv},
+	{Name: "windows.Cmd", Q: windows.Cmd},
+	{Name: "windows.Msiexec", Q: windows.Msiexec},
+	{Name: "windows.PSSingleQuote", Q: windows.PSSingleQuote},
+	{Name: "windows.PSDoubleQuote", Q: windows.PSDoubleQuote},
+	{Name: "windows.PwshDoubleQuote", Q: windows.PwshDoubleQuote},
+}
+
+// onlyQuoting hides all the optional interfaces of a Quoting.
+type onlyQuoting struct {
+	quote.Quoting
+}
+
+func TestEncoder_Decoder(t *testing.T) {
+	inputs := testutil.InputTests('"', '\'', '`', '$', '^', '\\')
+	for _, sq := range streamQuotings {
+		for _, q := range []quote.Quoting{sq.Q, onlyQuoting{sq.Q}} {
+			name := sq.Name
+			if _, ok := q.(onlyQuoting); ok {
+				name += ";buffered"
+			}
+			t.Run(name, func(t *testing.T) {
+				for _, it := range inputs {
+					var buf bytes.Buffer
+					e := quote.NewEncoder(&buf, q)
+					if _, err := io.Copy(e, iotest.OneByteReader(strings.NewReader(it.Input))); err != nil {
+						t.Fatalf("io.Copy(Encoder) = _, %v; want nil", err)
+					}
+					if err := e.Close(); err != nil {
+						t.Fatalf("Encoder.Close() = %v; want nil", err)
+					}
+					quoted := q.Quote(it.Input)
+					testutil.TestDiff(t, "Encoder("+it.Name+")", quoted, buf.String())
+
+					expected, err := q.Unquote(quoted)
+					if err != nil {
+						t.Fatalf("Unquote() = _, %v; want nil", err)
+					}
+					unquoted, err := io.ReadAll(quote.NewDecoder(iotest.OneByteReader(strings.NewReader(quoted)), q))
+					if err != nil {
+						t.Fatalf("io.ReadAll(Decoder) = _, %v; want nil", err)
+					}
+					testutil.TestDiff(t, "Decoder("+it.Name+")", expected, string(unquoted))
+				}
+			})
+		}
+	}
+}
+
+func TestDecoder_ShouldFail(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Q     quote.Quoting
+		Input string
+		Err   error
+	}{
+		{
+			Name:  "unix.SingleQuote;char after string",
+			Q:     unix.SingleQuote,
+			Input: "'a'a",
+			Err: &quote.SyntaxError{
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.SingleQuote",
+				Offset:  3,
+			},
+		},
+		{
+			Name:  "unix.ANSIC;invalid escape sequence",
+			Q:     unix.ANSIC,
+			Input: `$'abc\Uffffffff'`,
+			Err: &quote.SyntaxError{
+				Msg:     "invalid escape sequence `\\Uffffffff`",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "unix.ANSIC",
+				Offset:  5,
+			},
+		},
+		{
+			Name:  "windows.Msiexec;unterminated string",
+			Q:     windows.Msiexec,
+			Input: `"a""`,
+			Err: &quote.SyntaxError{
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "windows.Msiexec",
+				Offset:  4,
+			},
+		},
+		{
+			Name:  "windows.PSDoubleQuote;unescaped $",
+			Q:     windows.PSDoubleQuote,
+			Input: `"abc$"`,
+			Err: &quote.SyntaxError{
+				Msg:     "unescaped special character U+0024 '$'",
+				Kind:    quote.ErrUnsupportedChar,
+				Dialect: "windows.PSDoubleQuote",
+				Offset:  4,
+			},
+		},
+		{
+			Name:  "Chain;char after string",
+			Q:     quote.Chain(unix.SingleQuote, unix.DoubleQuote),
+			Input: `"'a'"x`,
+			Err: &quote.SyntaxError{
+				Msg:     "layer 1: character U+0078 'x' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.DoubleQuote",
+				Offset:  5,
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			_, err := io.ReadAll(quote.NewDecoder(iotest.OneByteReader(strings.NewReader(td.Input)), td.Q))
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Errorf("io.ReadAll(Decoder) mismatch (-want +got):\n%s", diff)
+			}
+			_, err = td.Q.Unquote(td.Input)
+			expected := *td.Err.(*quote.SyntaxError)
+			expected.Input = td.Input
+			if diff := cmp.Diff(&expected, err); diff != "" {
+				t.Errorf("Unquote() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEncoder_Close(t *testing.T) {
+	var buf bytes.Buffer
+	e := quote.NewEncoder(&buf, unix.SingleQuote)
+	if err := e.Close(); err != nil {
+		t.Fatalf("Encoder.Close() = %v; want nil", err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Encoder.Close() = %v; want nil", err)
+	}
+	testutil.TestDiff(t, "Encoder", "''", buf.String())
+	if _, err := e.Write([]byte("a")); err == nil {
+		t.Fatal("Encoder.Write() = _, nil; want error")
+	}
+}
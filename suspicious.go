@@ -0,0 +1,102 @@
+package quote
+
+import (
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SuspicionKind is a kind of Suspicion.
+type SuspicionKind int
+
+// Kinds of suspicions.
+const (
+	// SuspicionBidi is a bidirectional control character, like U+202E RIGHT-TO-LEFT OVERRIDE,
+	// reordering the text displayed after it.
+	SuspicionBidi SuspicionKind = iota + 1
+
+	// SuspicionInvisible is a character displayed as nothing,
+	// like U+200B ZERO WIDTH SPACE or U+3164 HANGUL FILLER.
+	SuspicionInvisible
+
+	// SuspicionConfusable is a character looking like an ASCII one special to shells,
+	// like U+201C LEFT DOUBLE QUOTATION MARK or U+3000 IDEOGRAPHIC SPACE.
+	SuspicionConfusable
+)
+
+var suspicionKinds = [...]string{
+	SuspicionBidi:       "bidirectional control",
+	SuspicionInvisible:  "invisible",
+	SuspicionConfusable: "confusable",
+}
+
+func (k SuspicionKind) String() string {
+	if k > 0 && int(k) < len(suspicionKinds) {
+		return suspicionKinds[k]
+	}
+	return "SuspicionKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Suspicion is a character that makes a string look different from what it is.
+type Suspicion struct {
+	Offset int           // byte offset of the character in the string
+	Rune   rune          // the character
+	Kind   SuspicionKind // kind of suspicion
+}
+
+// confusables are characters looking like quotes and other ASCII characters special to shells.
+// PowerShell treats some of them, like U+201C LEFT DOUBLE QUOTATION MARK, as quotes.
+var confusables = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00AB, Hi: 0x00AB, Stride: 1}, // «
+		{Lo: 0x00B4, Hi: 0x00B4, Stride: 1}, // ´
+		{Lo: 0x00BB, Hi: 0x00BB, Stride: 1}, // »
+		{Lo: 0x02B9, Hi: 0x02BC, Stride: 1}, // ʹ ʺ ʻ ʼ
+		{Lo: 0x02C8, Hi: 0x02C8, Stride: 1}, // ˈ
+		{Lo: 0x2010, Hi: 0x2015, Stride: 1}, // hyphens and dashes
+		{Lo: 0x2018, Hi: 0x201F, Stride: 1}, // ‘ ’ ‚ ‛ “ ” „ ‟
+		{Lo: 0x2032, Hi: 0x2037, Stride: 1}, // primes
+		{Lo: 0x2039, Hi: 0x203A, Stride: 1}, // ‹ ›
+		{Lo: 0x2044, Hi: 0x2044, Stride: 1}, // ⁄
+		{Lo: 0x2212, Hi: 0x2212, Stride: 1}, // −
+		{Lo: 0x2215, Hi: 0x2215, Stride: 1}, // ∕
+		{Lo: 0x2216, Hi: 0x2216, Stride: 1}, // ∖
+		{Lo: 0xFE68, Hi: 0xFE68, Stride: 1}, // ﹨
+		{Lo: 0xFF01, Hi: 0xFF5E, Stride: 1}, // fullwidth ASCII
+	},
+}
+
+// SuspicionOf returns the kind of suspicion raised by r, or 0 if r is not suspicious.
+func SuspicionOf(r rune) SuspicionKind {
+	switch {
+	case r < utf8.RuneSelf:
+		return 0
+	case unicode.Is(unicode.Bidi_Control, r):
+		return SuspicionBidi
+	case unicode.In(r, unicode.Join_Control, unicode.Other_Default_Ignorable_Code_Point),
+		unicode.Is(unicode.Cf, r) && !unicode.Is(unicode.Prepended_Concatenation_Mark, r):
+		return SuspicionInvisible
+	case unicode.Is(unicode.Zs, r), unicode.Is(confusables, r):
+		return SuspicionConfusable
+	default:
+		return 0
+	}
+}
+
+// Suspicious reports characters of s that make it look different from what it is
+// when displayed, like in code review tools: bidirectional controls reordering text
+// ("Trojan Source" attacks), invisible characters and characters looking like quotes,
+// blanks and other ASCII characters special to shells.
+// Characters of other scripts looking like ASCII letters are not reported.
+//
+// Quotings created with the DisplaySafe option of the unix and windows packages
+// escape bidirectional controls and invisible characters or fail to quote them.
+func Suspicious(s string) []Suspicion {
+	var suspicions []Suspicion
+	for i, r := range s {
+		if k := SuspicionOf(r); k != 0 {
+			suspicions = append(suspicions, Suspicion{Offset: i, Rune: r, Kind: k})
+		}
+	}
+	return suspicions
+}
@@ -0,0 +1,79 @@
+package quote_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+)
+
+func ExampleSuspicious() {
+	for _, s := range quote.Suspicious("rm -rf “/tmp/\u202Egnp.exe”") {
+		fmt.Printf("%d: %U %v\n", s.Offset, s.Rune, s.Kind)
+	}
+	// Output:
+	// 7: U+201C confusable
+	// 15: U+202E bidirectional control
+	// 25: U+201D confusable
+}
+
+func TestSuspicious(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Input      string
+		Suspicions []quote.Suspicion
+	}{
+		{
+			Name:  "ASCII",
+			Input: "echo 'a b' \"$c\"\t\x00\x7F",
+		},
+		{
+			Name:  "letters",
+			Input: "Привет, ταБЬℓσ 😇",
+		},
+		{
+			Name:  "bidi",
+			Input: "a\u2067b\u2069\u200F",
+			Suspicions: []quote.Suspicion{
+				{Offset: 1, Rune: '\u2067', Kind: quote.SuspicionBidi},
+				{Offset: 5, Rune: '\u2069', Kind: quote.SuspicionBidi},
+				{Offset: 8, Rune: '\u200F', Kind: quote.SuspicionBidi},
+			},
+		},
+		{
+			Name:  "invisible",
+			Input: "\u200Ba\u200D\u3164\uFEFF\U000E0041",
+			Suspicions: []quote.Suspicion{
+				{Offset: 0, Rune: '\u200B', Kind: quote.SuspicionInvisible},
+				{Offset: 4, Rune: '\u200D', Kind: quote.SuspicionInvisible},
+				{Offset: 7, Rune: '\u3164', Kind: quote.SuspicionInvisible},
+				{Offset: 10, Rune: '\uFEFF', Kind: quote.SuspicionInvisible},
+				{Offset: 13, Rune: '\U000E0041', Kind: quote.SuspicionInvisible},
+			},
+		},
+		{
+			Name:  "confusable",
+			Input: "‘a’\u00A0；",
+			Suspicions: []quote.Suspicion{
+				{Offset: 0, Rune: '‘', Kind: quote.SuspicionConfusable},
+				{Offset: 4, Rune: '’', Kind: quote.SuspicionConfusable},
+				{Offset: 7, Rune: '\u00A0', Kind: quote.SuspicionConfusable},
+				{Offset: 9, Rune: '；', Kind: quote.SuspicionConfusable},
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			if diff := cmp.Diff(td.Suspicions, quote.Suspicious(td.Input)); diff != "" {
+				t.Errorf("Suspicious() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSuspicionKind_String(t *testing.T) {
+	testutil.TestDiff(t, "String()", "invisible", quote.SuspicionInvisible.String())
+	testutil.TestDiff(t, "String()", "SuspicionKind(0)", quote.SuspicionKind(0).String())
+}
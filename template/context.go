@@ -0,0 +1,418 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// state is a lexical state of a shell script.
+type state uint8
+
+const (
+	// stateWord is unquoted shell code.
+	stateWord state = iota
+
+	// stateSingle is inside a single-quoted string ('…').
+	stateSingle
+
+	// stateDouble is inside a double-quoted string ("…").
+	stateDouble
+
+	// stateANSIC is inside an ANSI C quoted string ($'…').
+	stateANSIC
+
+	// stateComment is inside a comment (# …).
+	stateComment
+
+	// stateHeredoc is inside a here-document body.
+	stateHeredoc
+
+	// stateBacktick is inside a backtick command substitution (`…`).
+	stateBacktick
+
+	// stateParam is inside a parameter expansion (${…}).
+	stateParam
+
+	// stateArith is inside an arithmetic expansion ($((…))).
+	stateArith
+)
+
+var stateNames = [...]string{
+	stateWord:     "unquoted word",
+	stateSingle:   "single-quoted string",
+	stateDouble:   "double-quoted string",
+	stateANSIC:    "ANSI C quoted string",
+	stateComment:  "comment",
+	stateHeredoc:  "here-document",
+	stateBacktick: "backtick command substitution",
+	stateParam:    "parameter expansion",
+	stateArith:    "arithmetic expansion",
+}
+
+func (s state) String() string {
+	return stateNames[s]
+}
+
+// heredoc describes a here-document.
+type heredoc struct {
+	delim  string // delimiter line
+	quoted bool   // delimiter is quoted, the body is taken literally
+	dash   bool   // leading tabs are stripped (<<-)
+}
+
+// context is the lexical context of a position in a shell script.
+// Contexts are values: transitions return modified copies.
+type context struct {
+	state     state
+	escape    bool      // next character is escaped with a backslash
+	dollar    bool      // previous character is an unescaped dollar sign
+	wordStart bool      // at the start of a word, where # starts a comment
+	depth     int       // unmatched ( in stateWord and stateArith or { in stateParam
+	heredoc   heredoc   // current here-document in stateHeredoc
+	line      string    // text of the current line in stateHeredoc
+	lineKnown bool      // line contains no actions
+	pending   []heredoc // here-documents starting at the next newline
+	parent    *context  // context to return to at the end of a substitution or expansion
+}
+
+// initialContext is the context at the start of a script.
+var initialContext = context{state: stateWord, wordStart: true}
+
+func (c context) eq(d context) bool {
+	if c.state != d.state ||
+		c.escape != d.escape ||
+		c.dollar != d.dollar ||
+		c.wordStart != d.wordStart ||
+		c.depth != d.depth ||
+		c.heredoc != d.heredoc ||
+		c.line != d.line ||
+		c.lineKnown != d.lineKnown ||
+		len(c.pending) != len(d.pending) {
+		return false
+	}
+	for i := range c.pending {
+		if c.pending[i] != d.pending[i] {
+			return false
+		}
+	}
+	if c.parent == nil || d.parent == nil {
+		return c.parent == d.parent
+	}
+	return c.parent.eq(*d.parent)
+}
+
+// isFinal reports whether a script may end in c.
+func (c context) isFinal() bool {
+	return (c.state == stateWord || c.state == stateComment) &&
+		c.parent == nil &&
+		len(c.pending) == 0 &&
+		!c.escape
+}
+
+func (c context) String() string {
+	return c.state.String()
+}
+
+// push returns a context of state s nested in c.
+func (c context) push(s state) context {
+	parent := c
+	if parent.state == stateHeredoc {
+		parent.lineKnown = false
+	}
+	return context{
+		state:     s,
+		wordStart: s == stateWord,
+		parent:    &parent,
+	}
+}
+
+// pop returns the context c is nested in.
+func (c context) pop() context {
+	return *c.parent
+}
+
+// newline returns the context after an unescaped newline in stateWord or stateComment.
+func (c context) newline() context {
+	if len(c.pending) > 0 {
+		c.state = stateHeredoc
+		c.heredoc = c.pending[0]
+		c.pending = c.pending[1:]
+		c.line = ""
+		c.lineKnown = true
+		return c
+	}
+	c.state = stateWord
+	c.wordStart = true
+	return c
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// isOperator reports whether c ends a word.
+func isOperator(c byte) bool {
+	return strings.IndexByte(";&|()<>", c) >= 0
+}
+
+// transition returns the context after s.
+func transition(c context, s string) (context, error) {
+	for i := 0; i < len(s); i++ {
+		var err error
+		if c, i, err = step(c, s, i); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+// step returns the context after the character s[i] and the index of the last consumed character.
+func step(c context, s string, i int) (context, int, error) {
+	ch := s[i]
+	switch c.state {
+	case stateWord:
+		if c.escape {
+			c.escape = false
+			c.wordStart = false
+			return c, i, nil
+		}
+		if c.dollar {
+			c.dollar = false
+			switch ch {
+			case '\'':
+				c.state = stateANSIC
+				return c, i, nil
+			case '{':
+				c = c.push(stateParam)
+				c.depth = 1
+				return c, i, nil
+			case '(':
+				if i+1 < len(s) && s[i+1] == '(' {
+					c = c.push(stateArith)
+					c.depth = 2
+					return c, i + 1, nil
+				}
+				return c.push(stateWord), i, nil
+			}
+		}
+		switch ch {
+		case '\\':
+			c.escape = true
+		case '\'':
+			c.state = stateSingle
+		case '"':
+			c.state = stateDouble
+		case '`':
+			c = c.push(stateBacktick)
+		case '$':
+			c.dollar = true
+		case '#':
+			if c.wordStart {
+				c.state = stateComment
+				return c, i, nil
+			}
+		case '\n':
+			return c.newline(), i, nil
+		case '(':
+			c.depth++
+			c.wordStart = true
+			return c, i, nil
+		case ')':
+			if c.depth == 0 && c.parent != nil {
+				return c.pop(), i, nil
+			}
+			if c.depth > 0 {
+				c.depth--
+			}
+			c.wordStart = true
+			return c, i, nil
+		case '<':
+			if strings.HasPrefix(s[i:], "<<<") {
+				c.wordStart = true
+				return c, i + 2, nil
+			}
+			if strings.HasPrefix(s[i:], "<<") {
+				return heredocOperator(c, s, i)
+			}
+		}
+		c.wordStart = isBlank(ch) || isOperator(ch)
+	case stateSingle:
+		if ch == '\'' {
+			c.state = stateWord
+			c.wordStart = false
+		}
+	case stateDouble:
+		if c.escape {
+			c.escape = false
+			return c, i, nil
+		}
+		if c.dollar {
+			c.dollar = false
+			switch ch {
+			case '{':
+				c = c.push(stateParam)
+				c.depth = 1
+				return c, i, nil
+			case '(':
+				if i+1 < len(s) && s[i+1] == '(' {
+					c = c.push(stateArith)
+					c.depth = 2
+					return c, i + 1, nil
+				}
+				return c.push(stateWord), i, nil
+			}
+		}
+		switch ch {
+		case '\\':
+			c.escape = true
+		case '`':
+			c = c.push(stateBacktick)
+		case '$':
+			c.dollar = true
+		case '"':
+			c.state = stateWord
+			c.wordStart = false
+		}
+	case stateANSIC:
+		switch {
+		case c.escape:
+			c.escape = false
+		case ch == '\\':
+			c.escape = true
+		case ch == '\'':
+			c.state = stateWord
+			c.wordStart = false
+		}
+	case stateComment:
+		if ch == '\n' {
+			return c.newline(), i, nil
+		}
+	case stateHeredoc:
+		if !c.heredoc.quoted {
+			if c.escape {
+				c.escape = false
+				c.line += string(ch)
+				return c, i, nil
+			}
+			if c.dollar {
+				c.dollar = false
+				switch ch {
+				case '{':
+					c = c.push(stateParam)
+					c.depth = 1
+					return c, i, nil
+				case '(':
+					if i+1 < len(s) && s[i+1] == '(' {
+						c = c.push(stateArith)
+						c.depth = 2
+						return c, i + 1, nil
+					}
+					return c.push(stateWord), i, nil
+				}
+			}
+			switch ch {
+			case '\\':
+				c.escape = true
+			case '`':
+				return c.push(stateBacktick), i, nil
+			case '$':
+				c.dollar = true
+			}
+		}
+		if ch != '\n' {
+			c.line += string(ch)
+			return c, i, nil
+		}
+		line := c.line
+		if c.heredoc.dash {
+			line = strings.TrimLeft(line, "\t")
+		}
+		if c.lineKnown && line == c.heredoc.delim {
+			c.heredoc = heredoc{}
+			c.line = ""
+			return c.newline(), i, nil
+		}
+		c.line = ""
+		c.lineKnown = true
+	case stateBacktick:
+		switch {
+		case c.escape:
+			c.escape = false
+		case ch == '\\':
+			c.escape = true
+		case ch == '`':
+			return c.pop(), i, nil
+		}
+	case stateParam:
+		switch ch {
+		case '{':
+			c.depth++
+		case '}':
+			if c.depth--; c.depth == 0 {
+				return c.pop(), i, nil
+			}
+		}
+	case stateArith:
+		switch ch {
+		case '(':
+			c.depth++
+		case ')':
+			if c.depth--; c.depth == 0 {
+				return c.pop(), i, nil
+			}
+		}
+	}
+	return c, i, nil
+}
+
+// heredocOperator parses the here-document operator (<< or <<-) at s[i:]
+// and returns the context after its delimiter word.
+func heredocOperator(c context, s string, i int) (context, int, error) {
+	var h heredoc
+	j := i + 2
+	if j < len(s) && s[j] == '-' {
+		h.dash = true
+		j++
+	}
+	for j < len(s) && isBlank(s[j]) {
+		j++
+	}
+	var (
+		delim strings.Builder
+		quote byte
+	)
+	for ; j < len(s); j++ {
+		ch := s[j]
+		if quote != 0 {
+			if ch == quote {
+				quote = 0
+			} else {
+				delim.WriteByte(ch)
+			}
+			continue
+		}
+		if isBlank(ch) || isOperator(ch) || ch == '\n' {
+			break
+		}
+		switch ch {
+		case '\'', '"':
+			quote = ch
+			h.quoted = true
+		case '\\':
+			h.quoted = true
+			if j+1 < len(s) {
+				j++
+				delim.WriteByte(s[j])
+			}
+		default:
+			delim.WriteByte(ch)
+		}
+	}
+	if quote != 0 || j == len(s) || delim.Len() == 0 {
+		return c, j, fmt.Errorf("here-document delimiter must be a literal word followed by a blank, an operator or a newline")
+	}
+	h.delim = delim.String()
+	c.pending = append(c.pending[:len(c.pending):len(c.pending)], h)
+	c.wordStart = false
+	return c, j - 1, nil
+}
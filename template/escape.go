@@ -0,0 +1,276 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/unix"
+)
+
+// Names of escaping functions added to pipelines.
+const (
+	escapeWordFunc    = "_shell_escapeWord"
+	escapeSingleFunc  = "_shell_escapeSingle"
+	escapeDoubleFunc  = "_shell_escapeDouble"
+	escapeANSICFunc   = "_shell_escapeANSIC"
+	escapeCommentFunc = "_shell_escapeComment"
+	escapeHeredocFunc = "_shell_escapeHeredoc"
+)
+
+var escapeFuncs = template.FuncMap{
+	escapeWordFunc:    escapeWord,
+	escapeSingleFunc:  escapeSingle,
+	escapeDoubleFunc:  escapeDouble,
+	escapeANSICFunc:   escapeANSIC,
+	escapeCommentFunc: escapeComment,
+	escapeHeredocFunc: escapeHeredoc,
+}
+
+// Error describes a problem encountered during template escaping.
+type Error struct {
+	Name     string // name of the template
+	Location string // location of the problem in the template, like "name:1:5"
+	Msg      string // description of the problem
+}
+
+func (e *Error) Error() string {
+	if e.Location != "" {
+		return "template: " + e.Location + ": " + e.Msg
+	}
+	return "template: " + e.Name + ": " + e.Msg
+}
+
+// escaper rewrites a template parse tree adding escaping functions to actions.
+type escaper struct {
+	tree *parse.Tree
+	err  error
+}
+
+func escapeTree(tree *parse.Tree) error {
+	e := &escaper{tree: tree}
+	c := e.escapeList(initialContext, tree.Root)
+	if e.err == nil && !c.isFinal() {
+		e.errorf(nil, "ends in %s", c)
+	}
+	return e.err
+}
+
+func (e *escaper) errorf(n parse.Node, format string, args ...any) {
+	if e.err != nil {
+		return
+	}
+	err := &Error{
+		Name: e.tree.Name,
+		Msg:  fmt.Sprintf(format, args...),
+	}
+	if n != nil {
+		err.Location, _ = e.tree.ErrorContext(n)
+	}
+	e.err = err
+}
+
+func (e *escaper) escapeList(c context, l *parse.ListNode) context {
+	if l == nil {
+		return c
+	}
+	for _, n := range l.Nodes {
+		if c = e.escapeNode(c, n); e.err != nil {
+			break
+		}
+	}
+	return c
+}
+
+func (e *escaper) escapeNode(c context, n parse.Node) context {
+	switch n := n.(type) {
+	case *parse.TextNode:
+		c2, err := transition(c, string(n.Text))
+		if err != nil {
+			e.errorf(n, "%s", err)
+		}
+		return c2
+	case *parse.ActionNode:
+		return e.escapeAction(c, n)
+	case *parse.IfNode:
+		return e.escapeBranch(c, &n.BranchNode, "if")
+	case *parse.RangeNode:
+		return e.escapeBranch(c, &n.BranchNode, "range")
+	case *parse.WithNode:
+		return e.escapeBranch(c, &n.BranchNode, "with")
+	case *parse.ListNode:
+		return e.escapeList(c, n)
+	case *parse.TemplateNode:
+		if !c.eq(initialContext) {
+			e.errorf(n, "{{template %q}} in %s, templates can be called only between commands", n.Name, c)
+		}
+		return c
+	}
+	return c
+}
+
+func (e *escaper) escapeBranch(c context, n *parse.BranchNode, name string) context {
+	c1 := e.escapeList(c, n.List)
+	if e.err != nil {
+		return c1
+	}
+	if name == "range" && !c1.eq(c) {
+		e.errorf(n, "{{range}} body ends in %s but starts in %s", c1, c)
+		return c1
+	}
+	c2 := e.escapeList(c, n.ElseList)
+	if e.err == nil && !c1.eq(c2) {
+		e.errorf(n, "{{%s}} branches end in different contexts: %s and %s", name, c1, c2)
+	}
+	return c1
+}
+
+func (e *escaper) escapeAction(c context, n *parse.ActionNode) context {
+	if len(n.Pipe.Decl) > 0 {
+		return c
+	}
+	if c.escape {
+		e.errorf(n, "action after backslash in %s", c)
+		return c
+	}
+	if c.dollar {
+		e.errorf(n, "action after dollar sign in %s", c)
+		return c
+	}
+	var args []parse.Node
+	switch c.state {
+	case stateWord:
+		args = []parse.Node{identifier(escapeWordFunc)}
+		c.wordStart = false
+	case stateSingle:
+		args = []parse.Node{identifier(escapeSingleFunc)}
+	case stateDouble:
+		args = []parse.Node{identifier(escapeDoubleFunc)}
+	case stateANSIC:
+		args = []parse.Node{identifier(escapeANSICFunc)}
+	case stateComment:
+		args = []parse.Node{identifier(escapeCommentFunc)}
+	case stateHeredoc:
+		prefix := c.line
+		if !c.lineKnown {
+			prefix = ""
+		}
+		args = []parse.Node{
+			identifier(escapeHeredocFunc),
+			stringNode(c.heredoc.delim),
+			boolNode(c.heredoc.quoted),
+			boolNode(c.heredoc.dash),
+			stringNode(prefix),
+			boolNode(c.lineKnown),
+		}
+		c.lineKnown = false
+	default:
+		e.errorf(n, "action in %s", c)
+		return c
+	}
+	n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+		NodeType: parse.NodeCommand,
+		Pos:      n.Pos,
+		Args:     args,
+	})
+	return c
+}
+
+func identifier(name string) *parse.IdentifierNode {
+	return parse.NewIdentifier(name)
+}
+
+func stringNode(s string) *parse.StringNode {
+	return &parse.StringNode{NodeType: parse.NodeString, Quoted: strconv.Quote(s), Text: s}
+}
+
+func boolNode(b bool) *parse.BoolNode {
+	return &parse.BoolNode{NodeType: parse.NodeBool, True: b}
+}
+
+// stringify returns the textual representation of v as printed by text/template.
+func stringify(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// quoteInner returns s quoted with q without its opening and closing quotes.
+func quoteInner(q quote.Quoting, s string, open, close int) (string, error) {
+	quoted, err := q.(quote.StrictQuoting).QuoteStrict(s)
+	if err != nil {
+		return "", err
+	}
+	return quoted[open : len(quoted)-close], nil
+}
+
+func escapeWord(v any) (string, error) {
+	return unix.SingleQuote.(quote.StrictQuoting).QuoteStrict(stringify(v))
+}
+
+func escapeSingle(v any) (string, error) {
+	return quoteInner(unix.SingleQuote, stringify(v), 1, 1)
+}
+
+func escapeDouble(v any) (string, error) {
+	return quoteInner(unix.DoubleQuote, stringify(v), 1, 1)
+}
+
+func escapeANSIC(v any) (string, error) {
+	return quoteInner(unix.ANSIC, stringify(v), 2, 1)
+}
+
+func escapeComment(v any) (string, error) {
+	s := stringify(v)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return "", fmt.Errorf("value %q contains a newline that would end the comment", s)
+	}
+	return s, nil
+}
+
+var heredocReplacer = strings.NewReplacer(`\`, `\\`, "$", `\$`, "`", "\\`")
+
+func escapeHeredoc(delim string, quoted, dash bool, prefix string, prefixKnown bool, v any) (string, error) {
+	s := stringify(v)
+	if strings.IndexByte(s, 0) >= 0 {
+		return "", fmt.Errorf("value %q contains a NUL character", s)
+	}
+	lines := strings.Split(s, "\n")
+	for i, part := range lines {
+		line := part
+		if i == 0 {
+			line = prefix + line
+		}
+		if dash {
+			line = strings.TrimLeft(line, "\t")
+		}
+		last := i == len(lines)-1
+		switch {
+		case part == "":
+			// The line is made of the template text only.
+			if !last && (i > 0 || prefixKnown) && line == delim {
+				return "", fmt.Errorf("value %q ends the here-document", s)
+			}
+		case i == 0 && !prefixKnown:
+			// Unknown text before the value might complete the line to the delimiter.
+			if last && strings.Contains(delim, line) || !last && strings.HasSuffix(delim, line) {
+				return "", fmt.Errorf("value %q might end the here-document", s)
+			}
+		case last:
+			// Text after the value might complete the line to the delimiter.
+			if strings.HasPrefix(delim, line) {
+				return "", fmt.Errorf("value %q might end the here-document", s)
+			}
+		case line == delim:
+			return "", fmt.Errorf("value %q ends the here-document", s)
+		}
+	}
+	if quoted {
+		return s, nil
+	}
+	return heredocReplacer.Replace(s), nil
+}
@@ -0,0 +1,46 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package template_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/template"
+)
+
+func TestTemplate_Execute_Exec(t *testing.T) {
+	templates := map[string]string{
+		"word":    `printf '%s\n' {{.}}`,
+		"single":  `printf '%s\n' 'a{{.}}b'`,
+		"double":  `printf '%s\n' "a{{.}}b"`,
+		"heredoc": "cat <<EOF\na{{.}}b\nEOF\n",
+	}
+	for _, it := range testutil.InputTests('"', '\t', '\n', ' ', '$', '\'', '!', '`', '\\') {
+		if strings.IndexByte(it.Input, 0) >= 0 {
+			continue
+		}
+		it := it
+		for name, text := range templates {
+			name, text := name, text
+			t.Run(name+"/"+it.Name, func(t *testing.T) {
+				t.Parallel()
+				tmpl, err := template.New(name).Parse(text)
+				if err != nil {
+					t.Fatalf("Parse() = _, %v; want nil", err)
+				}
+				var b strings.Builder
+				if err := tmpl.Execute(&b, it.Input); err != nil {
+					t.Fatalf("Execute() = %v; want nil", err)
+				}
+				expected := it.Input
+				if name != "word" {
+					expected = "a" + expected + "b"
+				}
+				testutil.TestExecOutput(t, expected, "sh", "-c", b.String())
+			})
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package template implements data-driven templates for generating shell scripts
+// safe against code injection.
+//
+// It wraps text/template and provides a subset of its interface:
+// New, Must, Name, Parse, Funcs, Delims, Option, Lookup, Execute and ExecuteTemplate.
+// Unlike with text/template, every action is escaped according to the shell context it appears in:
+//
+//	unquoted word or assignment value   echo {{.}}, FOO={{.}}     single-quoted with unix.SingleQuote
+//	single-quoted string                '{{.}}'                   escaped as by unix.SingleQuote
+//	double-quoted string                "{{.}}"                   escaped as by unix.DoubleQuote
+//	ANSI C quoted string                $'{{.}}'                  escaped as by unix.ANSIC
+//	comment                             # {{.}}                   rejected if it contains a newline
+//	here-document body                  cat <<EOF ... EOF         rejected if it can end the here-document
+//
+// Actions inside backtick command substitutions (`…`), parameter expansions (${…})
+// and arithmetic expansions ($((…))), as well as actions right after a backslash
+// or a dollar sign, are rejected when the template is executed.
+//
+// The package only understands POSIX shell and bash syntax and escapes actions
+// with the unix quotings: no Windows dialect is supported, so scripts for other shells,
+// like cmd.exe or PowerShell, can't be generated with it.
+package template
+
+import (
+	"io"
+	"sync"
+	"text/template"
+	"text/template/parse"
+)
+
+// FuncMap is the type of the map defining the mapping from names to functions.
+type FuncMap = template.FuncMap
+
+// Template is a specialized text/template.Template that produces a safe shell script.
+type Template struct {
+	text *template.Template
+	ns   *nameSpace
+}
+
+// nameSpace is shared by all templates associated with each other.
+type nameSpace struct {
+	mu      sync.Mutex
+	escaped map[*parse.Tree]error // escaping result of each tree
+}
+
+// New allocates a new shell template with the given name.
+func New(name string) *Template {
+	return &Template{
+		text: template.New(name).Funcs(escapeFuncs),
+		ns:   &nameSpace{escaped: make(map[*parse.Tree]error)},
+	}
+}
+
+// Must is a helper that wraps a call to a function returning (*Template, error)
+// and panics if the error is non-nil.
+func Must(t *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// New allocates a new shell template associated with the given one.
+func (t *Template) New(name string) *Template {
+	return &Template{text: t.text.New(name), ns: t.ns}
+}
+
+// Name returns the name of the template.
+func (t *Template) Name() string {
+	return t.text.Name()
+}
+
+// Parse parses text as a template body for t.
+func (t *Template) Parse(text string) (*Template, error) {
+	t.ns.mu.Lock()
+	defer t.ns.mu.Unlock()
+	if _, err := t.text.Parse(text); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Funcs adds the elements of the argument map to the template's function map.
+func (t *Template) Funcs(funcMap FuncMap) *Template {
+	t.text.Funcs(funcMap)
+	return t
+}
+
+// Delims sets the action delimiters to the specified strings.
+func (t *Template) Delims(left, right string) *Template {
+	t.text.Delims(left, right)
+	return t
+}
+
+// Option sets options for the template, see text/template.Template.Option.
+func (t *Template) Option(opt ...string) *Template {
+	t.text.Option(opt...)
+	return t
+}
+
+// Lookup returns the template with the given name that is associated with t,
+// or nil if there is no such template.
+func (t *Template) Lookup(name string) *Template {
+	text := t.text.Lookup(name)
+	if text == nil {
+		return nil
+	}
+	return &Template{text: text, ns: t.ns}
+}
+
+// Execute applies a parsed template to the specified data object,
+// writing the output to w.
+func (t *Template) Execute(w io.Writer, data any) error {
+	if err := t.escape(); err != nil {
+		return err
+	}
+	return t.text.Execute(w, data)
+}
+
+// ExecuteTemplate applies the template associated with t that has the given name
+// to the specified data object and writes the output to w.
+func (t *Template) ExecuteTemplate(w io.Writer, name string, data any) error {
+	if err := t.escape(); err != nil {
+		return err
+	}
+	return t.text.ExecuteTemplate(w, name, data)
+}
+
+// escape escapes all associated templates not yet escaped.
+func (t *Template) escape() error {
+	t.ns.mu.Lock()
+	defer t.ns.mu.Unlock()
+	for _, text := range t.text.Templates() {
+		if text.Tree == nil {
+			continue
+		}
+		err, ok := t.ns.escaped[text.Tree]
+		if !ok {
+			err = escapeTree(text.Tree)
+			t.ns.escaped[text.Tree] = err
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package template_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote/template"
+)
+
+func Example() {
+	const script = `#!/bin/sh
+# Deploy {{.Name}}
+NAME={{.Name}}
+echo "Deploying $NAME to {{.Host}}"
+printf $'%s\n' $'{{.Motd}}'
+cat <<EOF >/etc/motd
+{{.Motd}}
+EOF
+`
+	t := template.Must(template.New("deploy").Parse(script))
+	err := t.Execute(os.Stdout, map[string]string{
+		"Name": "my app; rm -rf /",
+		"Host": `"$HOME"`,
+		"Motd": "Welcome, it's $(date)",
+	})
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// #!/bin/sh
+	// # Deploy my app; rm -rf /
+	// NAME='my app; rm -rf /'
+	// echo "Deploying $NAME to \"\$HOME\""
+	// printf $'%s\n' $'Welcome, it\'s $(date)'
+	// cat <<EOF >/etc/motd
+	// Welcome, it's \$(date)
+	// EOF
+}
+
+func TestTemplate_Execute(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Template string
+		Data     any
+		Output   string
+	}{
+		{Name: "word", Template: "echo {{.}}", Data: "a b", Output: "echo 'a b'"},
+		{Name: "word suffix", Template: "echo a{{.}}b", Data: "'", Output: `echo a''"'"''b`},
+		{Name: "assignment", Template: "X={{.}}; Y=$X", Data: "$(id)", Output: "X='$(id)'; Y=$X"},
+		{Name: "single", Template: "echo 'a {{.}} b'", Data: "it's", Output: `echo 'a it'"'"'s b'`},
+		{Name: "double", Template: `echo "{{.}}"`, Data: "`id` \"$x\"", Output: "echo \"\\`id\\` \\\"\\$x\\\"\""},
+		{Name: "double exclamation", Template: `echo "{{.}}"`, Data: "!x", Output: `echo "!x"`},
+		{Name: "ansic", Template: `echo $'{{.}}'`, Data: "a'\n", Output: `echo $'a\'\n'`},
+		{Name: "number", Template: "sleep {{.}}", Data: 10, Output: "sleep '10'"},
+		{Name: "comment", Template: "true # {{.}}\necho {{.}}", Data: "a; b", Output: "true # a; b\necho 'a; b'"},
+		{Name: "hash inside word", Template: "echo a#{{.}}", Data: "b c", Output: "echo a#'b c'"},
+		{Name: "command substitution", Template: "echo $(echo {{.}})", Data: "a b", Output: "echo $(echo 'a b')"},
+		{Name: "heredoc", Template: "cat <<EOF\n$x {{.}}\nEOF\necho {{.}}", Data: "`a` $b \\", Output: "cat <<EOF\n$x \\`a\\` \\$b \\\\\nEOF\necho '`a` $b \\'"},
+		{Name: "quoted heredoc", Template: "cat <<'EOF'\n{{.}}\nEOF\n", Data: "$b\nEOFX", Output: "cat <<'EOF'\n$b\nEOFX\nEOF\n"},
+		{Name: "dash heredoc", Template: "cat <<-EOF\n\t{{.}}\n\tEOF\n", Data: "a", Output: "cat <<-EOF\n\ta\n\tEOF\n"},
+		{Name: "here-string", Template: "cat <<<{{.}}", Data: "a b", Output: "cat <<<'a b'"},
+		{Name: "if", Template: "echo {{if .}}{{.}}{{else}}none{{end}}", Data: "a b", Output: "echo 'a b'"},
+		{Name: "range", Template: "ls{{range .}} {{.}}{{end}}", Data: []string{"a b", "c"}, Output: "ls 'a b' 'c'"},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			tmpl, err := template.New(td.Name).Parse(td.Template)
+			if err != nil {
+				t.Fatalf("Parse() = _, %v; want nil", err)
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, td.Data); err != nil {
+				t.Fatalf("Execute() = %v; want nil", err)
+			}
+			if s := b.String(); s != td.Output {
+				t.Errorf("Execute() output = %q; want %q", s, td.Output)
+			}
+		})
+	}
+}
+
+func TestTemplate_Execute_EscapeError(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Template string
+		Msg      string
+	}{
+		{Name: "backtick", Template: "echo `echo {{.}}`", Msg: "action in backtick command substitution"},
+		{Name: "backtick in double", Template: "echo \"`echo {{.}}`\"", Msg: "action in backtick command substitution"},
+		{Name: "parameter expansion", Template: "echo ${x:-{{.}}}", Msg: "action in parameter expansion"},
+		{Name: "arithmetic expansion", Template: "echo $(( {{.}} + 1 ))", Msg: "action in arithmetic expansion"},
+		{Name: "after dollar", Template: "echo ${{.}}", Msg: "action after dollar sign in unquoted word"},
+		{Name: "after backslash", Template: `echo "\{{.}}"`, Msg: "action after backslash in double-quoted string"},
+		{Name: "unterminated", Template: "echo '{{.}}", Msg: "ends in single-quoted string"},
+		{Name: "unterminated heredoc", Template: "cat <<EOF\n{{.}}\n", Msg: "ends in here-document"},
+		{Name: "pending heredoc", Template: "cat <<EOF", Msg: "here-document delimiter must be a literal word followed by a blank, an operator or a newline"},
+		{Name: "if branches", Template: "echo {{if .}}'{{end}}", Msg: "{{if}} branches end in different contexts: single-quoted string and unquoted word"},
+		{Name: "range body", Template: "echo {{range .}}\"{{end}}", Msg: "{{range}} body ends in double-quoted string but starts in unquoted word"},
+		{Name: "template call", Template: `{{define "a"}}x{{end}}echo "{{template "a"}}"`, Msg: `{{template "a"}} in double-quoted string, templates can be called only between commands`},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			tmpl, err := template.New(td.Name).Parse(td.Template)
+			if err != nil {
+				t.Fatalf("Parse() = _, %v; want nil", err)
+			}
+			for i := 0; i < 2; i++ {
+				err = tmpl.Execute(&strings.Builder{}, "a")
+				var e *template.Error
+				if !errors.As(err, &e) {
+					t.Fatalf("Execute() = %v; want *template.Error", err)
+				}
+				if e.Msg != td.Msg {
+					t.Errorf("Execute() error message = %q; want %q", e.Msg, td.Msg)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplate_Execute_ValueError(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Template string
+		Data     string
+	}{
+		{Name: "newline in comment", Template: "# {{.}}\n", Data: "a\nrm -rf /"},
+		{Name: "delimiter", Template: "cat <<EOF\n{{.}}\nEOF\n", Data: "a\nEOF\nrm -rf /"},
+		{Name: "delimiter prefix", Template: "cat <<EOF\n{{.}}x\nEOF\n", Data: "a\nE"},
+		{Name: "delimiter suffix", Template: "cat <<EOF\nE{{.}}\nEOF\n", Data: "OF\nrm -rf /"},
+		{Name: "dash delimiter", Template: "cat <<-EOF\n{{.}}\nEOF\n", Data: "a\n\t\tEOF\nrm -rf /"},
+		{Name: "quoted delimiter", Template: "cat <<'EOF'\n{{.}}\nEOF\n", Data: "EOF\nrm -rf /"},
+		{Name: "NUL in heredoc", Template: "cat <<EOF\n{{.}}\nEOF\n", Data: "a\x00"},
+		{Name: "NUL in word", Template: "echo {{.}}", Data: "a\x00"},
+		{Name: "NUL in double", Template: `echo "{{.}}"`, Data: "a\x00"},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			tmpl, err := template.New(td.Name).Parse(td.Template)
+			if err != nil {
+				t.Fatalf("Parse() = _, %v; want nil", err)
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, td.Data); err == nil {
+				t.Errorf("Execute() = nil, output %q; want error", b.String())
+			}
+		})
+	}
+}
@@ -8,133 +8,254 @@ import (
 	"unicode/utf8"
 
 	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
 )
 
 type ansiC struct {
 	unixQuote
 }
 
-func (ansiC) Quote(s string) string {
-	var buf strings.Builder
-	for _, r := range s {
+func (ansiC) String() string { return "unix.ANSIC" }
+
+func (q ansiC) Quote(s string) string {
+	return string(q.AppendQuote(make([]byte, 0, len(s)+3), s))
+}
+
+func (q ansiC) QuoteStrict(s string) (string, error) {
+	return quoteStrict(q, s)
+}
+
+func (ansiC) AppendQuote(dst []byte, s string) []byte {
+	var q ansiCQuoter
+	dst, _, _ = q.Transform(dst, s, true)
+	return dst
+}
+
+func (ansiC) NewQuoter() quote.Transformer {
+	return &ansiCQuoter{}
+}
+
+func (q ansiC) Unquote(s string) (string, error) {
+	b, err := q.AppendUnquote(make([]byte, 0, len(s)), s)
+	return string(b), err
+}
+
+func (q ansiC) AppendUnquote(dst []byte, s string) ([]byte, error) {
+	return quoteutil.AppendUnquote(dst, s, q)
+}
+
+func (ansiC) NewUnquoter() quote.Transformer {
+	return &ansiCUnquoter{}
+}
+
+func (q ansiC) Explain(s string) ([]quote.Span, error) {
+	u := ansiCUnquoter{spans: &quoteutil.SpanList{}}
+	b, _, err := u.unquote(nil, s, quoteutil.UnquoteAll, true)
+	return u.spans.Explain(b, err, q, s)
+}
+
+func (q ansiC) Split(s string) ([]string, error) {
+	return quoteutil.Split(s, q, isBlank)
+}
+
+func (q ansiC) QuotedPrefix(s string) (string, string, error) {
+	return quoteutil.QuotedPrefix(s, q)
+}
+
+func (ansiC) UnquoteMode(dst []byte, s string, mode quoteutil.Mode) ([]byte, int, error) {
+	var u ansiCUnquoter
+	return u.unquote(dst, s, mode, true)
+}
+
+// ansiCEscaped are ASCII characters escaped by ANSIC.
+var ansiCEscaped = func() *quoteutil.ASCIISet {
+	as := quoteutil.NewASCIISet("\"'?\\\x7F")
+	for c := byte(0); c < ' '; c++ {
+		as[c] = true
+	}
+	return as
+}()
+
+type ansiCQuoter struct {
+	started, displaySafe bool
+}
+
+func (q *ansiCQuoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	if !q.started {
+		dst = append(dst, "$'"...)
+		q.started = true
+	}
+	for i, width := 0, 0; i < len(src); i += width {
+		// Runs of characters needing no escaping are copied as is.
+		if width = ansiCEscaped.Skip(src[i:]); width > 0 {
+			dst = append(dst, src[i:i+width]...)
+			continue
+		}
+		if !atEOF && !utf8.FullRuneInString(src[i:]) {
+			return dst, i, nil
+		}
+		var r rune
+		r, width = utf8.DecodeRuneInString(src[i:])
+		if r == utf8.RuneError && width == 1 {
+			// Invalid UTF-8 bytes are escaped as is.
+			dst = quoteutil.AppendHex(dst, `\x`, rune(src[i]), 2)
+			continue
+		}
 		switch r {
 		case '\a':
-			buf.WriteString(`\a`)
+			dst = append(dst, `\a`...)
 		case '\b':
-			buf.WriteString(`\b`)
+			dst = append(dst, `\b`...)
 		case '\x1B':
-			buf.WriteString(`\e`)
+			dst = append(dst, `\e`...)
 		case '\f':
-			buf.WriteString(`\f`)
+			dst = append(dst, `\f`...)
 		case '\n':
-			buf.WriteString(`\n`)
+			dst = append(dst, `\n`...)
 		case '\r':
-			buf.WriteString(`\r`)
+			dst = append(dst, `\r`...)
 		case '\t':
-			buf.WriteString(`\t`)
+			dst = append(dst, `\t`...)
 		case '\v':
-			buf.WriteString(`\v`)
+			dst = append(dst, `\v`...)
 		case '"', '\'', '?', '\\':
-			buf.WriteRune('\\')
-			buf.WriteRune(r)
+			dst = append(dst, '\\', byte(r))
 		default:
 			switch {
 			case r < 0x20:
-				buf.WriteString(fmt.Sprintf("\\x%02X", r))
-			case strconv.IsPrint(r):
-				buf.WriteRune(r)
+				dst = quoteutil.AppendHex(dst, `\x`, r, 2)
+			case strconv.IsPrint(r) && !(q.displaySafe && quoteutil.IsHidden(r)):
+				dst = utf8.AppendRune(dst, r)
 			default:
 				if r < 0x10000 {
-					buf.WriteString(fmt.Sprintf("\\u%04X", r))
+					dst = quoteutil.AppendHex(dst, `\u`, r, 4)
 				} else {
-					buf.WriteString(fmt.Sprintf("\\U%08X", r))
+					dst = quoteutil.AppendHex(dst, `\U`, r, 8)
 				}
 			}
 		}
 	}
-	return "$'" + buf.String() + "'"
+	if atEOF {
+		dst = append(dst, '\'')
+	}
+	return dst, len(src), nil
+}
+
+// ansiCMaxEscape is the maximum length of an escape sequence in bytes (\UXXXXXXXX).
+const ansiCMaxEscape = 10
+
+type ansiCUnquoter struct {
+	inQuote bool
+	spans   *quoteutil.SpanList
+}
+
+func (u *ansiCUnquoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	return u.unquote(dst, src, quoteutil.UnquoteAll, atEOF)
 }
 
-func (ansiC) Unquote(s string) (string, error) {
+func (u *ansiCUnquoter) unquote(dst []byte, s string, mode quoteutil.Mode, atEOF bool) ([]byte, int, error) {
 	var (
-		r       rune
-		inQuote bool
-		buf     strings.Builder
-		b       = make([]rune, 9)
+		r        rune
+		i, width int
 	)
-	for i, width := 0, 0; i < len(s); i += width {
+	for ; i < len(s); i += width {
+		if !atEOF && !utf8.FullRuneInString(s[i:]) {
+			break
+		}
 		r, width = utf8.DecodeRuneInString(s[i:])
-		if !inQuote {
+		n := len(dst)
+		if !u.inQuote {
 			if strings.HasPrefix(s[i:], "$'") {
-				inQuote = true
+				u.inQuote = true
 				width++
+				u.spans.Add(quote.SpanOpenQuote, i, i+width, n, n)
 				continue
 			}
-			return "", &quote.SyntaxError{
-				Msg:    fmt.Sprintf("character %#U outside of quoted string", r),
-				Offset: i + 1,
+			if !atEOF && s[i:] == "$" {
+				break
 			}
+			if mode == quoteutil.UnquoteAll || mode == quoteutil.UnquotePrefix && i == 0 {
+				return nil, 0, &quote.SyntaxError{
+					Msg:    fmt.Sprintf("character %#U outside of quoted string", r),
+					Kind:   quote.ErrOutsideQuotes,
+					Offset: i,
+				}
+			}
+			if mode == quoteutil.UnquotePrefix || isBlank(s[i]) {
+				break
+			}
+			dst = append(dst, s[i:i+width]...)
+			u.spans.Add(quote.SpanLiteral, i, i+width, n, len(dst))
+			continue
 		} else if r == '\'' {
-			inQuote = false
+			u.inQuote = false
+			u.spans.Add(quote.SpanCloseQuote, i, i+width, n, n)
 			continue
 		}
 		if r != '\\' {
-			buf.WriteRune(r)
+			dst = append(dst, s[i:i+width]...)
+			u.spans.Add(quote.SpanLiteral, i, i+width, n, len(dst))
 			continue
 		}
+		if !atEOF && len(s)-i < ansiCMaxEscape {
+			break
+		}
+		start := i
 		if i += width; i >= len(s) {
-			return "", &quote.SyntaxError{
+			return nil, 0, &quote.SyntaxError{
 				Msg:    "unterminated escape sequence",
-				Offset: len(s),
+				Kind:   quote.ErrBadEscape,
+				Offset: start,
 			}
 		}
 		r, width = utf8.DecodeRuneInString(s[i:])
+		kind := quote.SpanEscape
 		switch r {
 		case 'a':
-			buf.WriteRune('\a')
+			dst = append(dst, '\a')
 		case 'b':
-			buf.WriteRune('\b')
-		case 'e':
-			buf.WriteRune('\x1B')
-		case 'E':
-			buf.WriteRune('\x1B')
+			dst = append(dst, '\b')
+		case 'e', 'E':
+			dst = append(dst, '\x1B')
 		case 'f':
-			buf.WriteRune('\f')
+			dst = append(dst, '\f')
 		case 'n':
-			buf.WriteRune('\n')
+			dst = append(dst, '\n')
 		case 'r':
-			buf.WriteRune('\r')
+			dst = append(dst, '\r')
 		case 't':
-			buf.WriteRune('\t')
+			dst = append(dst, '\t')
 		case 'v':
-			buf.WriteRune('\v')
+			dst = append(dst, '\v')
 		case '"', '?', '\'', '\\':
-			buf.WriteRune(r)
+			dst = append(dst, byte(r))
 		case 'c':
 			if i += width; i >= len(s) {
-				return "", &quote.SyntaxError{
+				return nil, 0, &quote.SyntaxError{
 					Msg:    "unterminated escape sequence `\\c`",
-					Offset: len(s),
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
 			r, width = utf8.DecodeRuneInString(s[i:])
 			switch {
 			case r == '?':
-				buf.WriteByte(0x7F)
+				dst = append(dst, 0x7F)
 			case r >= 'a' && r <= 'z':
 				r -= 'a' - 'A'
 				fallthrough
 			case r >= '@' && r <= '_':
-				buf.WriteRune(r - '@')
+				dst = append(dst, byte(r-'@'))
 			default:
-				return "", &quote.SyntaxError{
+				return nil, 0, &quote.SyntaxError{
 					Msg:    fmt.Sprintf("invalid character %#U in escape sequence `\\c`", r),
-					Offset: i + 1,
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
 		case 'x', 'u', 'U':
 			n := 0
-			switch s[i] {
+			switch r {
 			case 'x':
 				n = 2
 			case 'u':
@@ -142,63 +263,63 @@ func (ansiC) Unquote(s string) (string, error) {
 			case 'U':
 				n = 8
 			}
-			b = append(b[:0], r)
-			for ; n > 0 && i+width < len(s); n-- {
-				r1, width1 := utf8.DecodeRuneInString(s[i+width:])
-				if (r1 >= '0' && r1 <= '9') || (r1 >= 'a' && r1 <= 'f') || (r1 >= 'A' && r1 <= 'F') {
-					b = append(b, r1)
-					i += width
-					width = width1
-				} else {
-					break
-				}
+			j := i + 1
+			for ; j < len(s) && j-i-1 < n && quoteutil.IsHex(s[j]); j++ {
 			}
-			if len(b) == 1 {
-				return "", &quote.SyntaxError{
-					Msg:    fmt.Sprintf("unterminated escape sequence `\\%s`", string(b)),
-					Offset: i + 1,
+			if j == i+1 {
+				return nil, 0, &quote.SyntaxError{
+					Msg:    fmt.Sprintf("unterminated escape sequence `\\%s`", s[i:j]),
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
-			v, err := strconv.ParseUint(string(b[1:]), 16, 64)
+			seq := s[i:j]
+			i, width = j-1, 1
+			v, err := strconv.ParseUint(seq[1:], 16, 64)
 			if err != nil || v > utf8.MaxRune {
-				return "", &quote.SyntaxError{
-					Msg:    fmt.Sprintf("invalid escape sequence `\\%s`", string(b)),
-					Offset: i + 1,
+				return nil, 0, &quote.SyntaxError{
+					Msg:    fmt.Sprintf("invalid escape sequence `\\%s`", seq),
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
-			buf.WriteRune(rune(v))
+			if r == 'x' {
+				// \xHH is a byte, not a character, like octal escape sequences.
+				dst = append(dst, byte(v))
+			} else {
+				dst = utf8.AppendRune(dst, rune(v))
+			}
 		case '0', '1', '2', '3', '4', '5', '6', '7':
-			b = append(b[:0], r)
-			for n := 2; n > 0 && i+width < len(s); n-- {
-				r1, width1 := utf8.DecodeRuneInString(s[i+width:])
-				if r1 >= '0' && r1 <= '7' {
-					b = append(b, r1)
-					i += width
-					width = width1
-				} else {
-					break
-				}
+			j := i + 1
+			for ; j < len(s) && j-i < 3 && s[j] >= '0' && s[j] <= '7'; j++ {
 			}
-			v, err := strconv.ParseUint(string(b), 8, 8)
+			seq := s[i:j]
+			i, width = j-1, 1
+			v, err := strconv.ParseUint(seq, 8, 8)
 			if err != nil {
-				return "", &quote.SyntaxError{
-					Msg:    fmt.Sprintf("invalid escape sequence `\\%s`", string(b)),
-					Offset: i + 1,
+				return nil, 0, &quote.SyntaxError{
+					Msg:    fmt.Sprintf("invalid escape sequence `\\%s`", seq),
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
-			buf.WriteByte(byte(v))
+			dst = append(dst, byte(v))
 		default:
-			buf.WriteRune('\\')
-			buf.WriteRune(r)
+			// Unknown escape sequences are taken literally.
+			kind = quote.SpanLiteral
+			dst = append(dst, '\\')
+			dst = append(dst, s[i:i+width]...)
 		}
+		u.spans.Add(kind, start, i+width, n, len(dst))
 	}
-	if inQuote {
-		return "", &quote.SyntaxError{
+	if atEOF && u.inQuote {
+		return nil, 0, &quote.SyntaxError{
 			Msg:    "unterminated quoted string",
+			Kind:   quote.ErrUnterminated,
 			Offset: len(s),
 		}
 	}
-	return buf.String(), nil
+	return dst, i, nil
 }
 
 func (ansiC) QuoteBinary(b []byte) string {
@@ -236,7 +357,22 @@ func (ansiC) QuoteBinary(b []byte) string {
 	return "$'" + buf.String() + "'"
 }
 
-func (ansiC) UnquoteBinary(s string) ([]byte, error) {
+func (q ansiC) QuoteBinaryStrict(b []byte) (string, error) {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		return "", quoteutil.Unrepresentable(q.String(), string(b), i, fmt.Sprintf("unsupported character %#U", 0))
+	}
+	return q.QuoteBinary(b), nil
+}
+
+func (q ansiC) UnquoteBinary(s string) ([]byte, error) {
+	b, err := unquoteBinary(s)
+	if err != nil {
+		return nil, quoteutil.SyntaxError(err, q, s, 0)
+	}
+	return b, nil
+}
+
+func unquoteBinary(s string) ([]byte, error) {
 	var (
 		buf     bytes.Buffer
 		inQuote bool
@@ -251,7 +387,8 @@ func (ansiC) UnquoteBinary(s string) ([]byte, error) {
 			}
 			return nil, &quote.SyntaxError{
 				Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
-				Offset: i + 1,
+				Kind:   quote.ErrOutsideQuotes,
+				Offset: i,
 			}
 		} else if s[i] == '\'' {
 			inQuote = false
@@ -261,10 +398,12 @@ func (ansiC) UnquoteBinary(s string) ([]byte, error) {
 			buf.WriteByte(s[i])
 			continue
 		}
+		start := i
 		if i++; i >= len(s) {
 			return nil, &quote.SyntaxError{
 				Msg:    "unterminated escape sequence",
-				Offset: len(s),
+				Kind:   quote.ErrBadEscape,
+				Offset: start,
 			}
 		}
 		switch s[i] {
@@ -290,7 +429,8 @@ func (ansiC) UnquoteBinary(s string) ([]byte, error) {
 			if i++; i >= len(s) {
 				return nil, &quote.SyntaxError{
 					Msg:    "unterminated escape sequence `\\c`",
-					Offset: len(s),
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
 			switch c := s[i]; {
@@ -304,7 +444,8 @@ func (ansiC) UnquoteBinary(s string) ([]byte, error) {
 			default:
 				return nil, &quote.SyntaxError{
 					Msg:    fmt.Sprintf("invalid character %#U in escape sequence `\\c`", c),
-					Offset: i + 1,
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
 		case 'x', 'u', 'U':
@@ -333,14 +474,16 @@ func (ansiC) UnquoteBinary(s string) ([]byte, error) {
 			if len(b) == 1 {
 				return nil, &quote.SyntaxError{
 					Msg:    fmt.Sprintf("unterminated escape sequence `\\%s`", string(b)),
-					Offset: i + 1,
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
 			v, err := strconv.ParseUint(string(b[1:]), 16, 64)
 			if err != nil || v > utf8.MaxRune {
 				return nil, &quote.SyntaxError{
 					Msg:    fmt.Sprintf("invalid escape sequence `\\%s`", string(b)),
-					Offset: i + 1,
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
 			if isByte {
@@ -363,7 +506,8 @@ func (ansiC) UnquoteBinary(s string) ([]byte, error) {
 			if err != nil {
 				return nil, &quote.SyntaxError{
 					Msg:    fmt.Sprintf("invalid escape sequence `\\%s`", string(b)),
-					Offset: i + 1,
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
 			buf.WriteByte(byte(v))
@@ -375,6 +519,7 @@ func (ansiC) UnquoteBinary(s string) ([]byte, error) {
 	if inQuote {
 		return nil, &quote.SyntaxError{
 			Msg:    "unterminated quoted string",
+			Kind:   quote.ErrUnterminated,
 			Offset: len(s),
 		}
 	}
@@ -392,6 +537,8 @@ func (ansiC) UnquoteBinary(s string) ([]byte, error) {
 //
 //  $'a b:\"c d\" \'e\'\'f\'  \"g\\\"\"'
 //
+// Invalid UTF-8 bytes are quoted as \xHH escape sequences.
+//
 // See https://www.gnu.org/software/bash/manual/html_node/ANSI_002dC-Quoting.html
 // for details.
 var ANSIC quote.BinaryQuoting = ansiC{}
@@ -45,6 +45,12 @@ func TestANSIC_Quote_QuoteBinary_Unquote_UnquoteBinary(t *testing.T) {
 			Output:       `$'\U0001000C\U00010027\U0001003B\U0001003E\U0001004E'`,
 			OutputBinary: `$'\xF0\x90\x80\x8C\xF0\x90\x80\xA7\xF0\x90\x80\xBB\xF0\x90\x80\xBE\xF0\x90\x81\x8E'`,
 		},
+		{
+			Name:         "invalid UTF-8",
+			Input:        "a\xFFж\xD0\xE2\x82\uFFFD",
+			Output:       "$'a\\xFFж\\xD0\\xE2\\x82\uFFFD'",
+			OutputBinary: `$'a\xFF\xD0\xB6\xD0\xE2\x82\xEF\xBF\xBD'`,
+		},
 	}
 	for _, td := range tests {
 		t.Run(td.Name, func(t *testing.T) {
@@ -129,80 +135,110 @@ func TestANSIC_Unquote_UnquoteBinary_ShouldFail(t *testing.T) {
 			Name:  "unterminated quoting start",
 			Input: "$",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0024 '$' outside of quoted string",
-				Offset: 1,
+				Msg:     "character U+0024 '$' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.ANSIC",
+				Input:   "$",
+				Offset:  0,
 			},
 		},
 		{
 			Name:  "unterminated string",
 			Input: `$'a`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated quoted string",
-				Offset: 3,
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "unix.ANSIC",
+				Input:   `$'a`,
+				Offset:  3,
 			},
 		},
 		{
 			Name:  "unterminated escape sequence",
 			Input: `$'\`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated escape sequence",
-				Offset: 3,
+				Msg:     "unterminated escape sequence",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "unix.ANSIC",
+				Input:   `$'\`,
+				Offset:  2,
 			},
 		},
 		{
 			Name:  "char outside of string",
 			Input: "a",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 1,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.ANSIC",
+				Input:   "a",
+				Offset:  0,
 			},
 		},
 		{
 			Name:  "char after string",
 			Input: "$'a'a",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 5,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.ANSIC",
+				Input:   "$'a'a",
+				Offset:  4,
 			},
 		},
 		{
 			Name:  `unterminated \c`,
 			Input: `$'\c`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated escape sequence `\\c`",
-				Offset: 4,
+				Msg:     "unterminated escape sequence `\\c`",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "unix.ANSIC",
+				Input:   `$'\c`,
+				Offset:  2,
 			},
 		},
 		{
 			Name:  `invalid \c`,
 			Input: `$'\c+a`,
 			Err: &quote.SyntaxError{
-				Msg:    "invalid character U+002B '+' in escape sequence `\\c`",
-				Offset: 5,
+				Msg:     "invalid character U+002B '+' in escape sequence `\\c`",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "unix.ANSIC",
+				Input:   `$'\c+a`,
+				Offset:  2,
 			},
 		},
 		{
 			Name:  `unterminated \x`,
 			Input: `$'\x`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated escape sequence `\\x`",
-				Offset: 4,
+				Msg:     "unterminated escape sequence `\\x`",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "unix.ANSIC",
+				Input:   `$'\x`,
+				Offset:  2,
 			},
 		},
 		{
 			Name:  `invalid \U`,
 			Input: `$'\Uffffffff `,
 			Err: &quote.SyntaxError{
-				Msg:    "invalid escape sequence `\\Uffffffff`",
-				Offset: 12,
+				Msg:     "invalid escape sequence `\\Uffffffff`",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "unix.ANSIC",
+				Input:   `$'\Uffffffff `,
+				Offset:  2,
 			},
 		},
 		{
 			Name:  "invalid octal escape",
 			Input: `$'\777`,
 			Err: &quote.SyntaxError{
-				Msg:    "invalid escape sequence `\\777`",
-				Offset: 6,
+				Msg:     "invalid escape sequence `\\777`",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "unix.ANSIC",
+				Input:   `$'\777`,
+				Offset:  2,
 			},
 		},
 	}
@@ -250,3 +286,44 @@ func TestANSIC_QuoteBinary_UnquoteBinary_InputTests(t *testing.T) {
 		})
 	}
 }
+
+func TestANSIC_Split(t *testing.T) {
+	args, err := ANSIC.(quote.Splitter).Split(`a $'b c' $'\n'x`)
+	if err != nil {
+		t.Fatalf("ANSIC.Split() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b c", "\nx"}, args); diff != "" {
+		t.Errorf("ANSIC.Split() mismatch (-want +got):\n%s", diff)
+	}
+	_, err = ANSIC.(quote.Splitter).Split(`a $'\`)
+	expected := &quote.SyntaxError{
+		Msg:     "unterminated escape sequence",
+		Kind:    quote.ErrBadEscape,
+		Dialect: "unix.ANSIC",
+		Input:   `a $'\`,
+		Offset:  4,
+	}
+	if diff := cmp.Diff(expected, err); diff != "" {
+		t.Errorf("ANSIC.Split() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestANSIC_QuotedPrefix(t *testing.T) {
+	token, rest, err := ANSIC.(quote.Prefixer).QuotedPrefix(`$'a\n'$'\'b' $'c'`)
+	if err != nil {
+		t.Fatalf("ANSIC.QuotedPrefix() = _, _, %v; want nil", err)
+	}
+	testutil.TestDiff(t, "ANSIC.QuotedPrefix() token", "a\n'b", token)
+	testutil.TestDiff(t, "ANSIC.QuotedPrefix() rest", ` $'c'`, rest)
+	_, _, err = ANSIC.(quote.Prefixer).QuotedPrefix(`$a`)
+	expected := &quote.SyntaxError{
+		Msg:     "character U+0024 '$' outside of quoted string",
+		Kind:    quote.ErrOutsideQuotes,
+		Dialect: "unix.ANSIC",
+		Input:   `$a`,
+		Offset:  0,
+	}
+	if diff := cmp.Diff(expected, err); diff != "" {
+		t.Errorf("ANSIC.QuotedPrefix() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,30 @@
+package unix
+
+import (
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+)
+
+var appendTests = []testutil.AppendTest{
+	{Name: "SingleQuote", Q: SingleQuote.(quote.Appender), Invalid: "a"},
+	{Name: "DoubleQuote", Q: DoubleQuote.(quote.Appender), Invalid: "a"},
+	{Name: "ANSIC", Q: ANSIC.(quote.Appender), Invalid: "a"},
+}
+
+func TestAppendQuote_AppendUnquote(t *testing.T) {
+	testutil.TestAppend(t, appendTests)
+}
+
+func TestAppendQuote_AppendUnquote_Allocs(t *testing.T) {
+	testutil.TestAppendAllocs(t, appendTests)
+}
+
+func BenchmarkAppendQuote(b *testing.B) {
+	testutil.BenchmarkAppendQuote(b, appendTests)
+}
+
+func BenchmarkAppendUnquote(b *testing.B) {
+	testutil.BenchmarkAppendUnquote(b, appendTests)
+}
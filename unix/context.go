@@ -0,0 +1,116 @@
+package unix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
+)
+
+// assignmentSafe are characters special in words but not in assignment values,
+// which are not split into fields, brace or pathname expanded.
+var assignmentSafe = quoteutil.NewASCIISet("#*=?[]{}")
+
+// heredocEscaped are characters escaped with a backslash in here-document bodies.
+var heredocEscaped = quoteutil.NewASCIISet("$\\`")
+
+// contextQuoting is a quoting of this package.
+type contextQuoting interface {
+	quote.Quoting
+	String() string
+	QuoteStrict(s string) (string, error)
+}
+
+// quoteContext quotes s with q such that it appears correctly in context c.
+func quoteContext(q contextQuoting, c quote.Context, s string) (string, error) {
+	switch c {
+	case quote.ContextArgument, quote.ContextPattern, quote.ContextParameterWord:
+		if s != "" && !q.MustQuote(s) {
+			return s, nil
+		}
+		return q.QuoteStrict(s)
+	case quote.ContextAssignment:
+		if s == "" {
+			return s, nil
+		}
+		t := s
+		if assignmentSafe.Index(s) >= 0 {
+			t = strings.Map(func(r rune) rune {
+				if r < 0x80 && assignmentSafe.Contains(byte(r)) {
+					return -1
+				}
+				return r
+			}, s)
+		}
+		if !q.MustQuote(t) {
+			return s, nil
+		}
+		return q.QuoteStrict(s)
+	case quote.ContextHeredoc:
+		// The delimiter is unknown, so a line of s might end the here-document.
+		if i := strings.IndexAny(s, "\x00\n"); i >= 0 {
+			return "", quoteutil.Unrepresentable(q.String(), s, i, fmt.Sprintf("unsupported character %#U", s[i]))
+		}
+		return escapeHeredoc(s), nil
+	case quote.ContextComment:
+		if i := strings.IndexAny(s, "\x00\n"); i >= 0 {
+			return "", quoteutil.Unrepresentable(q.String(), s, i, fmt.Sprintf("unsupported character %#U", s[i]))
+		}
+		return s, nil
+	default:
+		return "", quoteutil.Unrepresentable(q.String(), s, 0, "unsupported context "+c.String())
+	}
+}
+
+func (q singleQuote) QuoteContext(c quote.Context, s string) (string, error) {
+	return quoteContext(q, c, s)
+}
+
+func (q doubleQuote) QuoteContext(c quote.Context, s string) (string, error) {
+	return quoteContext(q, c, s)
+}
+
+func (q ansiC) QuoteContext(c quote.Context, s string) (string, error) {
+	return quoteContext(q, c, s)
+}
+
+// escapeHeredoc escapes characters of s special in here-document bodies.
+func escapeHeredoc(s string) string {
+	if heredocEscaped.Index(s) < 0 {
+		return s
+	}
+	b := make([]byte, 0, len(s)+2)
+	for i := 0; i < len(s); i++ {
+		if heredocEscaped.Contains(s[i]) {
+			b = append(b, '\\')
+		}
+		b = append(b, s[i])
+	}
+	return string(b)
+}
+
+// QuoteHeredoc returns s escaped to be the whole lines of the body of a here-document
+// ending with a line equal to delim, like cat <<EOF.
+// It returns an error of type *quote.UnrepresentableError if s contains NUL
+// or a line equal to delim, with or without leading tabs removed by <<-.
+//
+// Unlike quote.QuoteContext with quote.ContextHeredoc, which rejects any newline
+// as it doesn't know the delimiter, s may contain newlines.
+func QuoteHeredoc(delim, s string) (string, error) {
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		return "", quoteutil.Unrepresentable("", s, i, fmt.Sprintf("unsupported character %#U", 0))
+	}
+	for i := 0; i <= len(s); {
+		n := strings.IndexByte(s[i:], '\n')
+		if n < 0 {
+			n = len(s) - i
+		}
+		if strings.TrimLeft(s[i:i+n], "\t") == delim {
+			return "", quoteutil.Unrepresentable("", s, i, "here-document delimiter "+strconv.Quote(delim))
+		}
+		i += n + 1
+	}
+	return escapeHeredoc(s), nil
+}
@@ -0,0 +1,267 @@
+package unix
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+)
+
+func TestQuoteContext(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Q       quote.Quoting
+		Context quote.Context
+		Input   string
+		Output  string
+		Err     error
+	}{
+		{
+			Name:    "argument",
+			Q:       SingleQuote,
+			Context: quote.ContextArgument,
+			Input:   "abc",
+			Output:  "abc",
+		},
+		{
+			Name:    "argument;backslash",
+			Q:       SingleQuote,
+			Context: quote.ContextArgument,
+			Input:   `a\b`,
+			Output:  `'a\b'`,
+		},
+		{
+			Name:    "argument;NUL",
+			Q:       DoubleQuote,
+			Context: quote.ContextArgument,
+			Input:   "a\x00",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "unix.DoubleQuote",
+				Input:   "a\x00",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "assignment",
+			Q:       SingleQuote,
+			Context: quote.ContextAssignment,
+			Input:   "#a=*.[ch]{,}?",
+			Output:  "#a=*.[ch]{,}?",
+		},
+		{
+			Name:    "assignment;empty",
+			Q:       SingleQuote,
+			Context: quote.ContextAssignment,
+			Input:   "",
+			Output:  "",
+		},
+		{
+			Name:    "assignment;quoted",
+			Q:       DoubleQuote,
+			Context: quote.ContextAssignment,
+			Input:   "~/*.txt $a",
+			Output:  `"~/*.txt \$a"`,
+		},
+		{
+			Name:    "assignment;backslash",
+			Q:       SingleQuote,
+			Context: quote.ContextAssignment,
+			Input:   `a\*`,
+			Output:  `'a\*'`,
+		},
+		{
+			Name:    "heredoc",
+			Q:       ANSIC,
+			Context: quote.ContextHeredoc,
+			Input:   "'a' \"b\"\t*",
+			Output:  "'a' \"b\"\t*",
+		},
+		{
+			Name:    "heredoc;newline",
+			Q:       ANSIC,
+			Context: quote.ContextHeredoc,
+			Input:   "a\nEOF",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+000A",
+				Dialect: "unix.ANSIC",
+				Input:   "a\nEOF",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "heredoc;escaped",
+			Q:       SingleQuote,
+			Context: quote.ContextHeredoc,
+			Input:   "$a `b` \\c",
+			Output:  "\\$a \\`b\\` \\\\c",
+		},
+		{
+			Name:    "heredoc;NUL",
+			Q:       SingleQuote,
+			Context: quote.ContextHeredoc,
+			Input:   "a\x00",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "unix.SingleQuote",
+				Input:   "a\x00",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "comment",
+			Q:       SingleQuote,
+			Context: quote.ContextComment,
+			Input:   "it's $a `b`",
+			Output:  "it's $a `b`",
+		},
+		{
+			Name:    "comment;newline",
+			Q:       ANSIC,
+			Context: quote.ContextComment,
+			Input:   "a\nrm -rf /",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+000A",
+				Dialect: "unix.ANSIC",
+				Input:   "a\nrm -rf /",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "pattern",
+			Q:       SingleQuote,
+			Context: quote.ContextPattern,
+			Input:   "a.txt",
+			Output:  "a.txt",
+		},
+		{
+			Name:    "pattern;quoted",
+			Q:       ANSIC,
+			Context: quote.ContextPattern,
+			Input:   "*a|b)",
+			Output:  "$'*a|b)'",
+		},
+		{
+			Name:    "parameter word",
+			Q:       DoubleQuote,
+			Context: quote.ContextParameterWord,
+			Input:   "a}b",
+			Output:  `"a}b"`,
+		},
+		{
+			Name:    "unsupported",
+			Q:       SingleQuote,
+			Context: quote.Context(0),
+			Input:   "a",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported context Context(0)",
+				Dialect: "unix.SingleQuote",
+				Input:   "a",
+			},
+		},
+		{
+			Name:    "assignment;display-safe",
+			Q:       NewSingleQuote(DisplaySafe()),
+			Context: quote.ContextAssignment,
+			Input:   "a\u200Bb",
+			Err: &quote.UnrepresentableError{
+				Msg:     "invisible character U+200B",
+				Dialect: "unix.SingleQuote",
+				Input:   "a\u200Bb",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "assignment;ANSIC;display-safe",
+			Q:       NewANSIC(DisplaySafe()),
+			Context: quote.ContextAssignment,
+			Input:   "a\u200Bb",
+			Output:  `$'a\u200Bb'`,
+		},
+		{
+			Name:    "heredoc;ANSIC;display-safe",
+			Q:       NewANSIC(DisplaySafe()),
+			Context: quote.ContextHeredoc,
+			Input:   "a\u200Bb",
+			Err: &quote.UnrepresentableError{
+				Msg:     "invisible character U+200B",
+				Dialect: "unix.ANSIC",
+				Input:   "a\u200Bb",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "assignment;safe chars",
+			Q:       NewSingleQuote(SafeChars("$")),
+			Context: quote.ContextAssignment,
+			Input:   "$a*",
+			Output:  "$a*",
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			s, err := quote.QuoteContext(td.Q, td.Context, td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuoteContext() mismatch (-want +got):\n%s", diff)
+			}
+			testutil.TestDiff(t, "QuoteContext()", td.Output, s)
+		})
+	}
+}
+
+func TestQuoteHeredoc(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Delim  string
+		Input  string
+		Output string
+		Err    error
+	}{
+		{
+			Name:   "multiline",
+			Delim:  "EOF",
+			Input:  "$a\n`b`\nEOF2\n EOF",
+			Output: "\\$a\n\\`b\\`\nEOF2\n EOF",
+		},
+		{
+			Name:  "delimiter",
+			Delim: "EOF",
+			Input: "a\nEOF\nb",
+			Err: &quote.UnrepresentableError{
+				Msg:    `here-document delimiter "EOF"`,
+				Input:  "a\nEOF\nb",
+				Offset: 2,
+			},
+		},
+		{
+			Name:  "delimiter;leading tabs",
+			Delim: "EOF",
+			Input: "\t\tEOF",
+			Err: &quote.UnrepresentableError{
+				Msg:    `here-document delimiter "EOF"`,
+				Input:  "\t\tEOF",
+				Offset: 0,
+			},
+		},
+		{
+			Name:  "NUL",
+			Delim: "EOF",
+			Input: "a\x00",
+			Err: &quote.UnrepresentableError{
+				Msg:    "unsupported character U+0000",
+				Input:  "a\x00",
+				Offset: 1,
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			s, err := QuoteHeredoc(td.Delim, td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuoteHeredoc() mismatch (-want +got):\n%s", diff)
+			}
+			testutil.TestDiff(t, "QuoteHeredoc()", td.Output, s)
+		})
+	}
+}
@@ -3,11 +3,10 @@
 package unix
 
 import (
-	"bytes"
-	"os/exec"
 	"strings"
 	"testing"
 
+	"github.com/sergeymakinen/go-quote"
 	"github.com/sergeymakinen/go-quote/internal/testutil"
 )
 
@@ -26,7 +25,7 @@ func TestDoubleQuote_Quote_Exec(t *testing.T) {
 		it := it
 		t.Run(it.Name, func(t *testing.T) {
 			t.Parallel()
-			testutil.TestExecOutput(t, strings.ReplaceAll(it.Input, "!", `\!`), "/bin/sh", "-c", `printf '%s\n' `+DoubleQuote.Quote(it.Input))
+			testutil.TestExecOutput(t, it.Input, "/bin/sh", "-c", `printf '%s\n' `+DoubleQuote.Quote(it.Input))
 		})
 	}
 }
@@ -60,15 +59,69 @@ func TestANSIC_QuoteBinary_Exec(t *testing.T) {
 	}
 }
 
-var ansiCShell string
-
-func init() {
-	// macOS uses an ancient Bash, so testing for \uxxxx support
-	for _, s := range []string{"bash", "zsh"} {
-		out, err := exec.Command(s, "-c", `printf '%s\n' $'\u200D'`).Output()
-		if err == nil && !bytes.Contains(out, []byte("$")) && !bytes.Contains(out, []byte(`200D`)) {
-			ansiCShell = s
-			break
+func TestQuoteContext_Exec(t *testing.T) {
+	scripts := map[quote.Context]func(s, input string) string{
+		quote.ContextAssignment: func(s, _ string) string {
+			return "a=" + s + "\nprintf '%s\\n' \"$a\""
+		},
+		quote.ContextHeredoc: func(s, _ string) string {
+			return "a=b\ncat <<EOF\n" + s + "\nEOF"
+		},
+		quote.ContextPattern: func(s, input string) string {
+			// The pattern must match the input only.
+			return "case x in " + s + ") exit 1;; esac\ncase " + SingleQuote.Quote(input) + " in " + s + ") printf '%s\\n' " + SingleQuote.Quote(input) + ";; esac"
+		},
+		quote.ContextParameterWord: func(s, _ string) string {
+			return "printf '%s\\n' ${a-" + s + "}"
+		},
+	}
+	for _, it := range testutil.InputTests('\'', '\t', '\n', ' ', '"') {
+		it := it
+		for c, script := range scripts {
+			c, script := c, script
+			t.Run(c.String()+"/"+it.Name, func(t *testing.T) {
+				t.Parallel()
+				if c == quote.ContextHeredoc && strings.Contains(it.Input, "\n") {
+					t.Skip("newlines are tested with QuoteHeredoc")
+				}
+				s, err := quote.QuoteContext(SingleQuote, c, it.Input)
+				if err != nil {
+					t.Fatalf("QuoteContext() = _, %v; want nil", err)
+				}
+				testutil.TestExecOutput(t, it.Input, "sh", "-c", script(s, it.Input))
+			})
 		}
 	}
 }
+
+func TestQuoteHeredoc_Exec(t *testing.T) {
+	for _, it := range testutil.InputTests('\'', '\t', '\n', ' ', '"') {
+		it := it
+		t.Run(it.Name, func(t *testing.T) {
+			t.Parallel()
+			s, err := QuoteHeredoc("EOF", it.Input)
+			if err != nil {
+				t.Fatalf("QuoteHeredoc() = _, %v; want nil", err)
+			}
+			testutil.TestExecOutput(t, it.Input, "sh", "-c", "a=b\ncat <<EOF\n"+s+"\nEOF")
+		})
+	}
+}
+
+func TestJoin_Exec(t *testing.T) {
+	args := []string{"printf", "%s|", `a\b`, `c\`, "d e", "", "$f", "!g"}
+	tests := []struct {
+		Name string
+		Q    quote.Quoting
+	}{
+		{Name: "SingleQuote", Q: SingleQuote},
+		{Name: "DoubleQuote", Q: DoubleQuote},
+	}
+	for _, td := range tests {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			testutil.TestExecOutput(t, strings.Join(args[2:], "|")+"|", "sh", "-c", quote.Join(td.Q, args))
+		})
+	}
+}
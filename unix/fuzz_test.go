@@ -0,0 +1,108 @@
+package unix
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+)
+
+func FuzzSingleQuote(f *testing.F) {
+	for _, it := range testutil.InputTests('\'', '\t', '\n', ' ', '"') {
+		f.Add(it.Input)
+	}
+	sh, _ := exec.LookPath("sh")
+	f.Fuzz(func(t *testing.T, s string) {
+		quoted := fuzzRoundTrip(t, SingleQuote, s)
+		if sh != "" {
+			fuzzExec(t, s, sh, quoted)
+		}
+	})
+}
+
+func FuzzDoubleQuote(f *testing.F) {
+	for _, it := range testutil.InputTests('"', '\t', '\n', ' ', '$', '\'') {
+		f.Add(it.Input)
+	}
+	sh, _ := exec.LookPath("sh")
+	f.Fuzz(func(t *testing.T, s string) {
+		quoted := fuzzRoundTrip(t, DoubleQuote, s)
+		if sh != "" {
+			fuzzExec(t, s, sh, quoted)
+		}
+	})
+}
+
+func FuzzANSIC(f *testing.F) {
+	for _, it := range testutil.InputTests('\'', '\t', '\n', ' ', '$', '"') {
+		f.Add(it.Input)
+	}
+	sh := ansiCShell
+	f.Fuzz(func(t *testing.T, s string) {
+		quoted := fuzzRoundTrip(t, ANSIC, s)
+		if sh != "" {
+			fuzzExec(t, s, sh, quoted)
+		}
+	})
+}
+
+func FuzzANSIC_QuoteBinary(f *testing.F) {
+	for _, it := range testutil.InputTests('\'', '\t', '\n', ' ', '$', '"') {
+		f.Add([]byte(it.Input))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		quoted := ANSIC.(quote.BinaryQuoting).QuoteBinary(b)
+		unquoted, err := ANSIC.(quote.BinaryQuoting).UnquoteBinary(quoted)
+		if err != nil {
+			t.Fatalf("ANSIC.UnquoteBinary(%q) = _, %v; want nil", quoted, err)
+		}
+		if !bytes.Equal(unquoted, b) {
+			t.Fatalf("ANSIC.UnquoteBinary(%q) = %q; want %q", quoted, unquoted, b)
+		}
+	})
+}
+
+// fuzzRoundTrip checks that s quoted with q unquotes back to s and returns the quoted string.
+// It skips s if q can't represent it.
+func fuzzRoundTrip(t *testing.T, q quote.Quoting, s string) string {
+	t.Helper()
+	quoted, err := q.(quote.StrictQuoting).QuoteStrict(s)
+	if err != nil {
+		t.Skip(err)
+	}
+	unquoted, err := q.Unquote(quoted)
+	if err != nil {
+		t.Fatalf("%v.Unquote(%q) = _, %v; want nil", q, quoted, err)
+	}
+	if unquoted != s {
+		t.Fatalf("%v.Unquote(%q) = %q; want %q", q, quoted, unquoted, s)
+	}
+	return quoted
+}
+
+// fuzzExec checks that the shell prints expected for quoted.
+func fuzzExec(t *testing.T, expected, shell, quoted string) {
+	t.Helper()
+	out, err := exec.Command(shell, "-c", `printf '%s' `+quoted).Output()
+	if err != nil {
+		t.Fatalf("Cmd.Output() = _, %v; want nil\nCmd: %s -c %q", err, shell, `printf '%s' `+quoted)
+	}
+	if string(out) != expected {
+		t.Fatalf("%s printed %q for %s; want %q", shell, out, quoted, expected)
+	}
+}
+
+var ansiCShell string
+
+func init() {
+	// macOS uses an ancient Bash, so testing for \uxxxx support
+	for _, s := range []string{"bash", "zsh"} {
+		out, err := exec.Command(s, "-c", `printf '%s\n' $'\u200D'`).Output()
+		if err == nil && !bytes.Contains(out, []byte("$")) && !bytes.Contains(out, []byte(`200D`)) {
+			ansiCShell = s
+			break
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package unix
+
+import (
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
+)
+
+// Option customizes a quoting returned by a constructor, like NewSingleQuote.
+// Options other than DisplaySafe only change what MustQuote reports:
+// strings are still quoted and unquoted the same way.
+type Option func(*options)
+
+type options = quoteutil.Options
+
+// SafeChars makes MustQuote treat chars as safe, like "=" and ":" for dd or rsync arguments.
+// Making characters special to the shell safe breaks command lines joined with quote.Join.
+func SafeChars(chars string) Option {
+	return func(o *options) { o.Safe += chars }
+}
+
+// UnsafeChars makes MustQuote treat chars as unsafe, so strings containing them are quoted.
+func UnsafeChars(chars string) Option {
+	return func(o *options) { o.Unsafe += chars }
+}
+
+// AlwaysQuote makes MustQuote report true for every string, so quote.Join quotes every argument.
+func AlwaysQuote() Option {
+	return func(o *options) { o.Always = true }
+}
+
+// DisplaySafe makes quoted strings display as what they are, protecting against "Trojan Source" attacks:
+// ANSIC escapes bidirectional controls and invisible characters, reported by quote.Suspicious,
+// and QuoteStrict of other quotings fails on them, as they can't be escaped.
+// MustQuote reports true for strings with such characters.
+func DisplaySafe() Option {
+	return func(o *options) { o.DisplaySafe = true }
+}
+
+type customSingleQuote struct {
+	singleQuote
+	opts options
+}
+
+func (q customSingleQuote) MustQuote(s string) bool {
+	return q.opts.MustQuote(s, q.singleQuote.MustQuote)
+}
+
+func (q customSingleQuote) QuoteStrict(s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return q.singleQuote.QuoteStrict(s)
+}
+
+func (q customSingleQuote) QuoteBinaryStrict(b []byte) (string, error) {
+	return q.QuoteStrict(string(b))
+}
+
+func (q customSingleQuote) QuoteContext(c quote.Context, s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return quoteContext(q, c, s)
+}
+
+// NewSingleQuote returns SingleQuote customized with opts.
+// Like SingleQuote, it implements quote.StrictBinaryQuoting.
+func NewSingleQuote(opts ...Option) quote.Quoting {
+	return customSingleQuote{opts: quoteutil.NewOptions(opts)}
+}
+
+type customDoubleQuote struct {
+	doubleQuote
+	opts options
+}
+
+func (q customDoubleQuote) MustQuote(s string) bool {
+	return q.opts.MustQuote(s, q.doubleQuote.MustQuote)
+}
+
+func (q customDoubleQuote) QuoteStrict(s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return q.doubleQuote.QuoteStrict(s)
+}
+
+func (q customDoubleQuote) QuoteBinaryStrict(b []byte) (string, error) {
+	return q.QuoteStrict(string(b))
+}
+
+func (q customDoubleQuote) QuoteContext(c quote.Context, s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return quoteContext(q, c, s)
+}
+
+// NewDoubleQuote returns DoubleQuote customized with opts.
+// Like DoubleQuote, it implements quote.StrictBinaryQuoting.
+func NewDoubleQuote(opts ...Option) quote.Quoting {
+	return customDoubleQuote{opts: quoteutil.NewOptions(opts)}
+}
+
+type customANSIC struct {
+	ansiC
+	opts options
+}
+
+func (q customANSIC) MustQuote(s string) bool {
+	return q.opts.MustQuote(s, q.ansiC.MustQuote)
+}
+
+func (q customANSIC) Quote(s string) string {
+	return string(q.AppendQuote(make([]byte, 0, len(s)+3), s))
+}
+
+func (q customANSIC) QuoteStrict(s string) (string, error) {
+	return quoteStrict(q, s)
+}
+
+func (q customANSIC) AppendQuote(dst []byte, s string) []byte {
+	qr := ansiCQuoter{displaySafe: q.opts.DisplaySafe}
+	dst, _, _ = qr.Transform(dst, s, true)
+	return dst
+}
+
+func (q customANSIC) NewQuoter() quote.Transformer {
+	return &ansiCQuoter{displaySafe: q.opts.DisplaySafe}
+}
+
+func (q customANSIC) QuoteContext(c quote.Context, s string) (string, error) {
+	if c == quote.ContextHeredoc || c == quote.ContextComment {
+		if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+			return "", err
+		}
+	}
+	return quoteContext(q, c, s)
+}
+
+// NewANSIC returns ANSIC customized with opts.
+func NewANSIC(opts ...Option) quote.BinaryQuoting {
+	return customANSIC{opts: quoteutil.NewOptions(opts)}
+}
@@ -0,0 +1,164 @@
+package unix
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/quotetest"
+)
+
+func TestOptions_MustQuote(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Q     quote.Quoting
+		Input string
+		Want  bool
+	}{
+		{
+			Name:  "no options",
+			Q:     NewSingleQuote(),
+			Input: "if=/dev/zero",
+			Want:  true,
+		},
+		{
+			Name:  "safe chars",
+			Q:     NewSingleQuote(SafeChars("=:")),
+			Input: "if=/dev/zero",
+			Want:  false,
+		},
+		{
+			Name:  "safe chars;unsafe",
+			Q:     NewDoubleQuote(SafeChars("=:")),
+			Input: "user@host:a b",
+			Want:  true,
+		},
+		{
+			Name:  "unsafe chars",
+			Q:     NewSingleQuote(UnsafeChars("-")),
+			Input: "-rf",
+			Want:  true,
+		},
+		{
+			Name:  "unsafe chars override safe chars",
+			Q:     NewSingleQuote(SafeChars("-"), UnsafeChars("-")),
+			Input: "-rf",
+			Want:  true,
+		},
+		{
+			Name:  "always quote",
+			Q:     NewANSIC(AlwaysQuote()),
+			Input: "a",
+			Want:  true,
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			if got := td.Q.MustQuote(td.Input); got != td.Want {
+				t.Errorf("MustQuote(%q) = %v; want %v", td.Input, got, td.Want)
+			}
+		})
+	}
+}
+
+func TestOptions_Join(t *testing.T) {
+	args := []string{"dd", "if=/dev/zero", "of=a b", "bs=1M"}
+	testutil.TestDiff(t, "Join()", "dd if=/dev/zero 'of=a b' bs=1M", quote.Join(NewSingleQuote(SafeChars("=")), args))
+	testutil.TestDiff(t, "Join()", "'dd' 'if=/dev/zero' 'of=a b' 'bs=1M'", quote.Join(NewSingleQuote(AlwaysQuote()), args))
+}
+
+func TestOptions_Dialect(t *testing.T) {
+	tests := []struct {
+		Name string
+		Q    quote.Quoting
+		Opts quotetest.Options
+	}{
+		{
+			Name: "NewSingleQuote",
+			Q:    NewSingleQuote(SafeChars("=")),
+			Opts: quotetest.Options{Delim: '\'', UnsafeChars: []byte("\t\n \"")},
+		},
+		{
+			Name: "NewDoubleQuote",
+			Q:    NewDoubleQuote(SafeChars("=")),
+			Opts: quotetest.Options{Delim: '"', UnsafeChars: []byte("\t\n $'")},
+		},
+		{
+			Name: "NewANSIC",
+			Q:    NewANSIC(SafeChars("=")),
+			Opts: quotetest.Options{Delim: '\'', UnsafeChars: []byte("\t\n $\"")},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			quotetest.TestQuoting(t, td.Q, td.Opts)
+		})
+	}
+	if _, ok := NewANSIC().(quote.Explainer); !ok {
+		t.Error("NewANSIC() doesn't implement quote.Explainer")
+	}
+}
+
+func TestDisplaySafe(t *testing.T) {
+	const input = "a\u3164b\u202Ec"
+	tests := []struct {
+		Name   string
+		Q      quote.Quoting
+		Output string
+		Err    error
+	}{
+		{
+			Name:   "ANSIC",
+			Q:      NewANSIC(),
+			Output: "$'a\u3164b\\u202Ec'",
+		},
+		{
+			Name:   "ANSIC;display-safe",
+			Q:      NewANSIC(DisplaySafe()),
+			Output: `$'a\u3164b\u202Ec'`,
+		},
+		{
+			Name: "SingleQuote;display-safe",
+			Q:    NewSingleQuote(DisplaySafe()),
+			Err: &quote.UnrepresentableError{
+				Msg:     "invisible character U+3164",
+				Dialect: "unix.SingleQuote",
+				Input:   input,
+				Offset:  1,
+			},
+		},
+		{
+			Name: "DoubleQuote;display-safe",
+			Q:    NewDoubleQuote(DisplaySafe()),
+			Err: &quote.UnrepresentableError{
+				Msg:     "invisible character U+3164",
+				Dialect: "unix.DoubleQuote",
+				Input:   input,
+				Offset:  1,
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			quoted, err := td.Q.(quote.StrictQuoting).QuoteStrict(input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuoteStrict() mismatch (-want +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			testutil.TestDiff(t, "QuoteStrict()", td.Output, quoted)
+			unquoted, err := td.Q.Unquote(quoted)
+			if err != nil {
+				t.Fatalf("Unquote() = _, %v; want nil", err)
+			}
+			testutil.TestDiff(t, "Unquote()", input, unquoted)
+		})
+	}
+	q := NewANSIC(DisplaySafe())
+	if !q.MustQuote("a\u200Bb") {
+		t.Errorf("MustQuote(%q) = false; want true", "a\u200Bb")
+	}
+	testutil.TestDiff(t, "Join()", `ls $'a\u200Bb'`, quote.Join(q, []string{"ls", "a\u200Bb"}))
+}
@@ -6,58 +6,166 @@ import (
 	"strings"
 
 	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
 )
 
 type singleQuote struct {
 	unixQuote
 }
 
-func (singleQuote) Quote(s string) string {
-	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+func (singleQuote) String() string { return "unix.SingleQuote" }
+
+func (q singleQuote) Quote(s string) string {
+	return string(q.AppendQuote(make([]byte, 0, len(s)+2), s))
+}
+
+func (q singleQuote) QuoteStrict(s string) (string, error) {
+	return quoteStrict(q, s)
+}
+
+func (q singleQuote) QuoteBinary(b []byte) string {
+	return q.Quote(string(b))
+}
+
+func (q singleQuote) QuoteBinaryStrict(b []byte) (string, error) {
+	return q.QuoteStrict(string(b))
+}
+
+func (q singleQuote) UnquoteBinary(s string) ([]byte, error) {
+	return q.AppendUnquote(nil, s)
+}
+
+func (singleQuote) AppendQuote(dst []byte, s string) []byte {
+	var q singleQuoter
+	dst, _, _ = q.Transform(dst, s, true)
+	return dst
+}
+
+func (singleQuote) NewQuoter() quote.Transformer {
+	return &singleQuoter{}
+}
+
+func (q singleQuote) Unquote(s string) (string, error) {
+	b, err := q.AppendUnquote(make([]byte, 0, len(s)), s)
+	return string(b), err
+}
+
+func (q singleQuote) AppendUnquote(dst []byte, s string) ([]byte, error) {
+	return quoteutil.AppendUnquote(dst, s, q)
+}
+
+func (singleQuote) NewUnquoter() quote.Transformer {
+	return &singleUnquoter{}
+}
+
+func (q singleQuote) Explain(s string) ([]quote.Span, error) {
+	u := singleUnquoter{spans: &quoteutil.SpanList{}}
+	b, _, err := u.unquote(nil, s, quoteutil.UnquoteAll, true)
+	return u.spans.Explain(b, err, q, s)
+}
+
+func (q singleQuote) Split(s string) ([]string, error) {
+	return quoteutil.Split(s, q, isBlank)
 }
 
-func (singleQuote) Unquote(s string) (string, error) {
-	var (
-		buf                          strings.Builder
-		inSingleQuote, inDoubleQuote bool
-	)
-	for i := 0; i < len(s); i++ {
+func (q singleQuote) QuotedPrefix(s string) (string, string, error) {
+	return quoteutil.QuotedPrefix(s, q)
+}
+
+func (singleQuote) UnquoteMode(dst []byte, s string, mode quoteutil.Mode) ([]byte, int, error) {
+	var u singleUnquoter
+	return u.unquote(dst, s, mode, true)
+}
+
+type singleQuoter struct {
+	started bool
+}
+
+func (q *singleQuoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	if !q.started {
+		dst = append(dst, '\'')
+		q.started = true
+	}
+	n := len(src)
+	for {
+		i := strings.IndexByte(src, '\'')
+		if i < 0 {
+			break
+		}
+		dst = append(dst, src[:i]...)
+		dst = append(dst, `'"'"'`...)
+		src = src[i+1:]
+	}
+	dst = append(dst, src...)
+	if atEOF {
+		dst = append(dst, '\'')
+	}
+	return dst, n, nil
+}
+
+type singleUnquoter struct {
+	inSingleQuote, inDoubleQuote bool
+	spans                        *quoteutil.SpanList
+}
+
+func (u *singleUnquoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	return u.unquote(dst, src, quoteutil.UnquoteAll, atEOF)
+}
+
+func (u *singleUnquoter) unquote(dst []byte, s string, mode quoteutil.Mode, atEOF bool) ([]byte, int, error) {
+	i := 0
+loop:
+	for ; i < len(s); i++ {
+		n := len(dst)
 		switch s[i] {
 		case '\'':
-			if inDoubleQuote {
-				buf.WriteByte(s[i])
+			if u.inDoubleQuote {
+				dst = append(dst, s[i])
+				u.spans.Add(quote.SpanLiteral, i, i+1, n, len(dst))
 			} else {
-				inSingleQuote = !inSingleQuote
+				u.inSingleQuote = !u.inSingleQuote
+				u.spans.AddQuote(u.inSingleQuote, i, i+1, n)
 			}
 		case '"':
-			if inSingleQuote {
-				buf.WriteByte(s[i])
+			if u.inSingleQuote {
+				dst = append(dst, s[i])
+				u.spans.Add(quote.SpanLiteral, i, i+1, n, len(dst))
 			} else {
-				inDoubleQuote = !inDoubleQuote
+				u.inDoubleQuote = !u.inDoubleQuote
+				u.spans.AddQuote(u.inDoubleQuote, i, i+1, n)
 			}
 		default:
-			if inDoubleQuote {
-				return "", &quote.SyntaxError{
+			if u.inDoubleQuote {
+				return nil, 0, &quote.SyntaxError{
 					Msg:    fmt.Sprintf("unsupported character %#U in double quoted string", s[i]),
-					Offset: i + 1,
+					Kind:   quote.ErrUnsupportedChar,
+					Offset: i,
 				}
 			}
-			if !inSingleQuote {
-				return "", &quote.SyntaxError{
-					Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
-					Offset: i + 1,
+			if !u.inSingleQuote {
+				if mode == quoteutil.UnquoteAll || mode == quoteutil.UnquotePrefix && i == 0 {
+					return nil, 0, &quote.SyntaxError{
+						Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
+						Kind:   quote.ErrOutsideQuotes,
+						Offset: i,
+					}
+				}
+				if mode == quoteutil.UnquotePrefix || isBlank(s[i]) {
+					break loop
 				}
 			}
-			buf.WriteByte(s[i])
+			dst = append(dst, s[i])
+			u.spans.Add(quote.SpanLiteral, i, i+1, n, len(dst))
 		}
 	}
-	if inSingleQuote || inDoubleQuote {
-		return "", &quote.SyntaxError{
+	if atEOF && (u.inSingleQuote || u.inDoubleQuote) {
+		return nil, 0, &quote.SyntaxError{
 			Msg:    "unterminated quoted string",
+			Kind:   quote.ErrUnterminated,
 			Offset: len(s),
 		}
 	}
-	return buf.String(), nil
+	return dst, i, nil
 }
 
 // SingleQuote quotes and unquotes strings, surrounded by single quotes (')
@@ -71,6 +179,9 @@ func (singleQuote) Unquote(s string) (string, error) {
 //
 //  'a b:"c d" '"'"'e'"'"''"'"'f'"'"'  "g\""'
 //
+// It implements quote.StrictBinaryQuoting:
+// QuoteBinary quotes any bytes but NUL, including invalid UTF-8 sequences.
+//
 // See https://pubs.opengroup.org/onlinepubs/9699919799/utilities/V3_chap02.html#tag_18_02_02
 // for details.
 var SingleQuote quote.Quoting = singleQuote{}
@@ -79,68 +190,183 @@ type doubleQuote struct {
 	unixQuote
 }
 
-var doubleQuoteReplacer = strings.NewReplacer(
-	"!", `\!`,
-	`"`, `\"`,
-	"$", `\$`,
-	`\`, `\\`,
-	"`", "\\`",
-)
+func (doubleQuote) String() string { return "unix.DoubleQuote" }
+
+func (q doubleQuote) Quote(s string) string {
+	return string(q.AppendQuote(make([]byte, 0, len(s)+2), s))
+}
+
+func (q doubleQuote) QuoteStrict(s string) (string, error) {
+	return quoteStrict(q, s)
+}
+
+func (q doubleQuote) QuoteBinary(b []byte) string {
+	return q.Quote(string(b))
+}
+
+func (q doubleQuote) QuoteBinaryStrict(b []byte) (string, error) {
+	return q.QuoteStrict(string(b))
+}
+
+func (q doubleQuote) UnquoteBinary(s string) ([]byte, error) {
+	return q.AppendUnquote(nil, s)
+}
+
+func (doubleQuote) AppendQuote(dst []byte, s string) []byte {
+	var q doubleQuoter
+	dst, _, _ = q.Transform(dst, s, true)
+	return dst
+}
+
+func (doubleQuote) NewQuoter() quote.Transformer {
+	return &doubleQuoter{}
+}
+
+func (q doubleQuote) Unquote(s string) (string, error) {
+	b, err := q.AppendUnquote(make([]byte, 0, len(s)), s)
+	return string(b), err
+}
+
+func (q doubleQuote) AppendUnquote(dst []byte, s string) ([]byte, error) {
+	return quoteutil.AppendUnquote(dst, s, q)
+}
+
+func (doubleQuote) NewUnquoter() quote.Transformer {
+	return &doubleUnquoter{}
+}
+
+func (q doubleQuote) Explain(s string) ([]quote.Span, error) {
+	u := doubleUnquoter{spans: &quoteutil.SpanList{}}
+	b, _, err := u.unquote(nil, s, quoteutil.UnquoteAll, true)
+	return u.spans.Explain(b, err, q, s)
+}
+
+func (q doubleQuote) Split(s string) ([]string, error) {
+	return quoteutil.Split(s, q, isBlank)
+}
+
+func (q doubleQuote) QuotedPrefix(s string) (string, string, error) {
+	return quoteutil.QuotedPrefix(s, q)
+}
 
-func (doubleQuote) Quote(s string) string {
-	return `"` + doubleQuoteReplacer.Replace(s) + `"`
+func (doubleQuote) UnquoteMode(dst []byte, s string, mode quoteutil.Mode) ([]byte, int, error) {
+	var u doubleUnquoter
+	return u.unquote(dst, s, mode, true)
 }
 
-func (doubleQuote) Unquote(s string) (string, error) {
-	var (
-		buf     strings.Builder
-		inQuote bool
-	)
-	for i := 0; i < len(s); i++ {
+// doubleQuoteEscaped are characters escaped with a backslash by DoubleQuote.
+// Exclamation marks are not special inside double quotes in POSIX shells.
+var doubleQuoteEscaped = quoteutil.NewASCIISet("\"$\\`")
+
+type doubleQuoter struct {
+	started bool
+}
+
+func (q *doubleQuoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	if !q.started {
+		dst = append(dst, '"')
+		q.started = true
+	}
+	n := len(src)
+	for {
+		i := doubleQuoteEscaped.Index(src)
+		if i < 0 {
+			break
+		}
+		dst = append(dst, src[:i]...)
+		dst = append(dst, '\\', src[i])
+		src = src[i+1:]
+	}
+	dst = append(dst, src...)
+	if atEOF {
+		dst = append(dst, '"')
+	}
+	return dst, n, nil
+}
+
+type doubleUnquoter struct {
+	inQuote bool
+	spans   *quoteutil.SpanList
+}
+
+func (u *doubleUnquoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	return u.unquote(dst, src, quoteutil.UnquoteAll, atEOF)
+}
+
+func (u *doubleUnquoter) unquote(dst []byte, s string, mode quoteutil.Mode, atEOF bool) ([]byte, int, error) {
+	i := 0
+	for ; i < len(s); i++ {
+		n := len(dst)
 		if s[i] == '"' {
-			inQuote = !inQuote
+			u.inQuote = !u.inQuote
+			u.spans.AddQuote(u.inQuote, i, i+1, n)
 			continue
 		}
-		if !inQuote {
-			return "", &quote.SyntaxError{
-				Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
-				Offset: i + 1,
+		if !u.inQuote {
+			if mode == quoteutil.UnquoteAll || mode == quoteutil.UnquotePrefix && i == 0 {
+				return nil, 0, &quote.SyntaxError{
+					Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
+					Kind:   quote.ErrOutsideQuotes,
+					Offset: i,
+				}
 			}
+			if mode == quoteutil.UnquotePrefix || isBlank(s[i]) {
+				break
+			}
+			dst = append(dst, s[i])
+			u.spans.Add(quote.SpanLiteral, i, i+1, n, len(dst))
+			continue
 		}
+		start := i
 		escape := false
 		if s[i] == '\\' {
+			if i+1 >= len(s) && !atEOF {
+				break
+			}
 			escape = true
 			if i++; i >= len(s) {
-				return "", &quote.SyntaxError{
+				return nil, 0, &quote.SyntaxError{
 					Msg:    "unterminated escape sequence",
-					Offset: len(s),
+					Kind:   quote.ErrBadEscape,
+					Offset: i - 1,
 				}
 			}
 		}
 		switch s[i] {
-		case '!', '"', '$', '\\', '`':
+		case '"', '$', '\\', '`':
 			if !escape {
-				return "", &quote.SyntaxError{
+				return nil, 0, &quote.SyntaxError{
 					Msg:    fmt.Sprintf("unescaped special character %#U", s[i]),
-					Offset: i + 1,
+					Kind:   quote.ErrUnsupportedChar,
+					Offset: i,
 				}
 			}
-			escape = false
-			fallthrough
+			dst = append(dst, s[i])
+			u.spans.Add(quote.SpanEscape, start, i+1, n, len(dst))
+		case '\n':
+			if escape {
+				// An escaped newline is removed.
+				u.spans.Add(quote.SpanLineContinuation, start, i+1, n, n)
+				break
+			}
+			dst = append(dst, s[i])
+			u.spans.Add(quote.SpanLiteral, start, i+1, n, len(dst))
 		default:
 			if escape {
-				buf.WriteByte('\\')
+				dst = append(dst, '\\')
 			}
-			buf.WriteByte(s[i])
+			dst = append(dst, s[i])
+			u.spans.Add(quote.SpanLiteral, start, i+1, n, len(dst))
 		}
 	}
-	if inQuote {
-		return "", &quote.SyntaxError{
+	if atEOF && u.inQuote {
+		return nil, 0, &quote.SyntaxError{
 			Msg:    "unterminated quoted string",
+			Kind:   quote.ErrUnterminated,
 			Offset: len(s),
 		}
 	}
-	return buf.String(), nil
+	return dst, i, nil
 }
 
 // DoubleQuote quotes and unquotes strings, surrounded by double quotes (")
@@ -154,6 +380,16 @@ func (doubleQuote) Unquote(s string) (string, error) {
 //
 //  "a b:\"c d\" 'e''f'  \"g\\\"\""
 //
+// Exclamation marks are left as is, as they can't be escaped inside double quotes,
+// so interactive shells with history expansion, like bash, may expand them.
+// Likewise, a backslash before an exclamation mark is unquoted as a literal backslash.
+// Earlier versions escaped exclamation marks with a backslash, which POSIX shells keep.
+//
+// A backslash followed by a newline is a line continuation: both are removed when unquoting.
+//
+// It implements quote.StrictBinaryQuoting:
+// QuoteBinary quotes any bytes but NUL, including invalid UTF-8 sequences.
+//
 // See https://pubs.opengroup.org/onlinepubs/9699919799/utilities/V3_chap02.html#tag_18_02_03
 // for details.
 var DoubleQuote quote.Quoting = doubleQuote{}
@@ -100,32 +100,44 @@ func TestSingleQuote_Unquote_ShouldFail(t *testing.T) {
 			Name:  "unterminated string",
 			Input: `'a`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated quoted string",
-				Offset: 2,
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "unix.SingleQuote",
+				Input:   `'a`,
+				Offset:  2,
 			},
 		},
 		{
 			Name:  "char outside of string",
 			Input: "a",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 1,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.SingleQuote",
+				Input:   "a",
+				Offset:  0,
 			},
 		},
 		{
 			Name:  "char after string",
 			Input: "'a'a",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 4,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.SingleQuote",
+				Input:   "'a'a",
+				Offset:  3,
 			},
 		},
 		{
 			Name:  "not single quote in double quotes",
 			Input: `'a'"b"`,
 			Err: &quote.SyntaxError{
-				Msg:    "unsupported character U+0062 'b' in double quoted string",
-				Offset: 5,
+				Msg:     "unsupported character U+0062 'b' in double quoted string",
+				Kind:    quote.ErrUnsupportedChar,
+				Dialect: "unix.SingleQuote",
+				Input:   `'a'"b"`,
+				Offset:  4,
 			},
 		},
 	}
@@ -164,7 +176,7 @@ func TestDoubleQuote_Quote_Unquote(t *testing.T) {
 		{
 			Name:   "special char escaping",
 			Input:  "!\"$\\`",
-			Output: "\"\\!\\\"\\$\\\\\\`\"",
+			Output: "\"!\\\"\\$\\\\\\`\"",
 		},
 	}
 	for _, td := range tests {
@@ -194,6 +206,16 @@ func TestDoubleQuote_Unquote(t *testing.T) {
 			Input:  `"\p\z"`,
 			Output: `\p\z`,
 		},
+		{
+			Name:   "exclamation marks",
+			Input:  `"!\!"`,
+			Output: `!\!`,
+		},
+		{
+			Name:   "line continuation",
+			Input:  "\"a\\\nb\"",
+			Output: "ab",
+		},
 	}
 	for _, td := range tests {
 		t.Run(td.Name, func(t *testing.T) {
@@ -215,64 +237,77 @@ func TestDoubleQuote_Unquote_ShouldFail(t *testing.T) {
 			Name:  "unterminated string #1",
 			Input: `"a`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated quoted string",
-				Offset: 2,
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "unix.DoubleQuote",
+				Input:   `"a`,
+				Offset:  2,
 			},
 		},
 		{
 			Name:  "unterminated string #2",
 			Input: `"""`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated quoted string",
-				Offset: 3,
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "unix.DoubleQuote",
+				Input:   `"""`,
+				Offset:  3,
 			},
 		},
 		{
 			Name:  "unterminated escape sequence",
 			Input: `"\`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated escape sequence",
-				Offset: 2,
+				Msg:     "unterminated escape sequence",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "unix.DoubleQuote",
+				Input:   `"\`,
+				Offset:  1,
 			},
 		},
 		{
 			Name:  "char outside of string",
 			Input: "a",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 1,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.DoubleQuote",
+				Input:   "a",
+				Offset:  0,
 			},
 		},
 		{
 			Name:  "char after string",
 			Input: `"a"a`,
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 4,
-			},
-		},
-		{
-			Name:  "unescaped !",
-			Input: `"!"`,
-			Err: &quote.SyntaxError{
-				Msg:    "unescaped special character U+0021 '!'",
-				Offset: 2,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.DoubleQuote",
+				Input:   `"a"a`,
+				Offset:  3,
 			},
 		},
 		{
 			Name:  `unescaped $`,
 			Input: `"$"`,
 			Err: &quote.SyntaxError{
-				Msg:    "unescaped special character U+0024 '$'",
-				Offset: 2,
+				Msg:     "unescaped special character U+0024 '$'",
+				Kind:    quote.ErrUnsupportedChar,
+				Dialect: "unix.DoubleQuote",
+				Input:   `"$"`,
+				Offset:  1,
 			},
 		},
 		{
 			Name:  "unescaped `",
 			Input: "\"`\"",
 			Err: &quote.SyntaxError{
-				Msg:    "unescaped special character U+0060 '`'",
-				Offset: 2,
+				Msg:     "unescaped special character U+0060 '`'",
+				Kind:    quote.ErrUnsupportedChar,
+				Dialect: "unix.DoubleQuote",
+				Input:   "\"`\"",
+				Offset:  1,
 			},
 		},
 	}
@@ -298,3 +333,129 @@ func TestDoubleQuote_Quote_Unquote_InputTests(t *testing.T) {
 		})
 	}
 }
+
+func TestSingleQuote_Split(t *testing.T) {
+	args, err := SingleQuote.(quote.Splitter).Split(` echo 'a b'  '' c'd'"'"e` + "\t\n")
+	if err != nil {
+		t.Fatalf("SingleQuote.Split() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff([]string{"echo", "a b", "", "cd'e"}, args); diff != "" {
+		t.Errorf("SingleQuote.Split() mismatch (-want +got):\n%s", diff)
+	}
+	_, err = SingleQuote.(quote.Splitter).Split(`a 'b`)
+	expected := &quote.SyntaxError{
+		Msg:     "unterminated quoted string",
+		Kind:    quote.ErrUnterminated,
+		Dialect: "unix.SingleQuote",
+		Input:   `a 'b`,
+		Offset:  4,
+	}
+	if diff := cmp.Diff(expected, err); diff != "" {
+		t.Errorf("SingleQuote.Split() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDoubleQuote_Split(t *testing.T) {
+	args, err := DoubleQuote.(quote.Splitter).Split(`printf "%s\n" "a \"b\""`)
+	if err != nil {
+		t.Fatalf("DoubleQuote.Split() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff([]string{"printf", `%s\n`, `a "b"`}, args); diff != "" {
+		t.Errorf("DoubleQuote.Split() mismatch (-want +got):\n%s", diff)
+	}
+	_, err = DoubleQuote.(quote.Splitter).Split(`a "$"`)
+	expected := &quote.SyntaxError{
+		Msg:     "unescaped special character U+0024 '$'",
+		Kind:    quote.ErrUnsupportedChar,
+		Dialect: "unix.DoubleQuote",
+		Input:   `a "$"`,
+		Offset:  3,
+	}
+	if diff := cmp.Diff(expected, err); diff != "" {
+		t.Errorf("DoubleQuote.Split() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSingleQuote_DoubleQuote_QuotedPrefix(t *testing.T) {
+	tests := []struct {
+		Name               string
+		Q                  quote.Quoting
+		Input, Token, Rest string
+		Err                error
+	}{
+		{
+			Name:  "SingleQuote;token and rest",
+			Q:     SingleQuote,
+			Input: `'some file' -> "other"`,
+			Token: "some file",
+			Rest:  ` -> "other"`,
+		},
+		{
+			Name:  "SingleQuote;multiple strings",
+			Q:     SingleQuote,
+			Input: `'it'"'"'s'rest`,
+			Token: "it's",
+			Rest:  "rest",
+		},
+		{
+			Name:  "SingleQuote;whole string",
+			Q:     SingleQuote,
+			Input: `''`,
+			Token: "",
+			Rest:  "",
+		},
+		{
+			Name:  "DoubleQuote;token and rest",
+			Q:     DoubleQuote,
+			Input: `"a \"b\"" c`,
+			Token: `a "b"`,
+			Rest:  " c",
+		},
+		{
+			Name:  "SingleQuote;empty string",
+			Q:     SingleQuote,
+			Input: "",
+			Err: &quote.SyntaxError{
+				Msg:     "missing quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "unix.SingleQuote",
+				Input:   "",
+				Offset:  0,
+			},
+		},
+		{
+			Name:  "SingleQuote;unquoted string",
+			Q:     SingleQuote,
+			Input: "a 'b'",
+			Err: &quote.SyntaxError{
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "unix.SingleQuote",
+				Input:   "a 'b'",
+				Offset:  0,
+			},
+		},
+		{
+			Name:  "DoubleQuote;unterminated string",
+			Q:     DoubleQuote,
+			Input: `"a b`,
+			Err: &quote.SyntaxError{
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "unix.DoubleQuote",
+				Input:   `"a b`,
+				Offset:  4,
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			token, rest, err := td.Q.(quote.Prefixer).QuotedPrefix(td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuotedPrefix() mismatch (-want +got):\n%s", diff)
+			}
+			testutil.TestDiff(t, "QuotedPrefix() token", td.Token, token)
+			testutil.TestDiff(t, "QuotedPrefix() rest", td.Rest, rest)
+		})
+	}
+}
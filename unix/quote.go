@@ -1,11 +1,44 @@
 package unix
 
-import "regexp"
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
 
-var reUnsafeChars = regexp.MustCompile("[\\x00-\\x24&'()*;<=>?\\[\\]^`\\x7B-\\x7F\\x{00A0}]")
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
+)
+
+// unsafeChars are ASCII characters special to shells, along with control characters.
+// Strings with them or the no-break space (U+00A0) must be quoted.
+var unsafeChars = func() *quoteutil.ASCIISet {
+	as := quoteutil.NewASCIISet("&'()*;<=>?[\\]^`")
+	for c := byte(0); c <= '$'; c++ {
+		as[c] = true
+	}
+	for c := byte('{'); c < utf8.RuneSelf; c++ {
+		as[c] = true
+	}
+	return as
+}()
 
 type unixQuote struct{}
 
 func (unixQuote) MustQuote(s string) bool {
-	return reUnsafeChars.MatchString(s)
+	return unsafeChars.Index(s) >= 0 || strings.Contains(s, "\u00A0")
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+// quoteStrict quotes s with q unless it contains NUL bytes.
+func quoteStrict(q interface {
+	quote.Quoting
+	String() string
+}, s string) (string, error) {
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		return "", quoteutil.Unrepresentable(q.String(), s, i, fmt.Sprintf("unsupported character %#U", 0))
+	}
+	return q.Quote(s), nil
 }
@@ -0,0 +1,253 @@
+package unix
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+)
+
+func TestMustQuote(t *testing.T) {
+	// reUnsafeChars matches characters that must be quoted.
+	reUnsafeChars := regexp.MustCompile("[\\x00-\\x24&'()*;<=>?\\[\\\\\\]^`\\x7B-\\x7F\\x{00A0}]")
+	var inputs []string
+	for r := rune(0); r < 0x200; r++ {
+		inputs = append(inputs, "a"+string(r)+"b")
+	}
+	for c := 0x80; c < 0x100; c++ {
+		inputs = append(inputs, "a"+string([]byte{byte(c)})+"b", "\xC2"+string([]byte{byte(c)}))
+	}
+	for _, q := range []quote.Quoting{SingleQuote, DoubleQuote, ANSIC} {
+		for _, s := range inputs {
+			if got, want := q.MustQuote(s), reUnsafeChars.MatchString(s); got != want {
+				t.Errorf("%v.MustQuote(%q) = %v; want %v", q, s, got, want)
+			}
+		}
+	}
+}
+
+func TestQuoteStrict(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Q             quote.Quoting
+		Input, Output string
+		Err           error
+	}{
+		{
+			Name:   "SingleQuote",
+			Q:      SingleQuote,
+			Input:  "a\nb\xFF",
+			Output: "'a\nb\xFF'",
+		},
+		{
+			Name:  "SingleQuote;NUL",
+			Q:     SingleQuote,
+			Input: "a\x00b",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "unix.SingleQuote",
+				Input:   "a\x00b",
+				Offset:  1,
+			},
+		},
+		{
+			Name:   "DoubleQuote",
+			Q:      DoubleQuote,
+			Input:  "a\xFF$",
+			Output: "\"a\xFF\\$\"",
+		},
+		{
+			Name:  "DoubleQuote;NUL",
+			Q:     DoubleQuote,
+			Input: "\x00",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "unix.DoubleQuote",
+				Input:   "\x00",
+				Offset:  0,
+			},
+		},
+		{
+			Name:   "ANSIC",
+			Q:      ANSIC,
+			Input:  "a\nb",
+			Output: `$'a\nb'`,
+		},
+		{
+			Name:  "ANSIC;NUL",
+			Q:     ANSIC,
+			Input: "ab\x00",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "unix.ANSIC",
+				Input:   "ab\x00",
+				Offset:  2,
+			},
+		},
+		{
+			Name:   "ANSIC;invalid UTF-8",
+			Q:      ANSIC,
+			Input:  "ж\xFF",
+			Output: `$'ж\xFF'`,
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			quoted, err := td.Q.(quote.StrictQuoting).QuoteStrict(td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuoteStrict() mismatch (-want +got):\n%s", diff)
+			}
+			testutil.TestDiff(t, "QuoteStrict()", td.Output, quoted)
+		})
+	}
+}
+
+func TestQuoteBinaryStrict_UnquoteBinary(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Q      quote.Quoting
+		Input  []byte
+		Output string
+		Err    error
+	}{
+		{
+			Name:   "SingleQuote",
+			Q:      SingleQuote,
+			Input:  []byte("a'\xFF\xFE"),
+			Output: "'a'\"'\"'\xFF\xFE'",
+		},
+		{
+			Name:  "SingleQuote;NUL",
+			Q:     SingleQuote,
+			Input: []byte("\xFF\x00"),
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "unix.SingleQuote",
+				Input:   "\xFF\x00",
+				Offset:  1,
+			},
+		},
+		{
+			Name:   "DoubleQuote",
+			Q:      DoubleQuote,
+			Input:  []byte("$\x80"),
+			Output: "\"\\$\x80\"",
+		},
+		{
+			Name:  "DoubleQuote;NUL",
+			Q:     DoubleQuote,
+			Input: []byte("\x00"),
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "unix.DoubleQuote",
+				Input:   "\x00",
+				Offset:  0,
+			},
+		},
+		{
+			Name:   "ANSIC",
+			Q:      ANSIC,
+			Input:  []byte("a\xFF"),
+			Output: `$'a\xFF'`,
+		},
+		{
+			Name:  "ANSIC;NUL",
+			Q:     ANSIC,
+			Input: []byte("\xFFa\x00"),
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "unix.ANSIC",
+				Input:   "\xFFa\x00",
+				Offset:  2,
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			q := td.Q.(quote.StrictBinaryQuoting)
+			quoted, err := q.QuoteBinaryStrict(td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuoteBinaryStrict() mismatch (-want +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			testutil.TestDiff(t, "QuoteBinaryStrict()", td.Output, quoted)
+			unquoted, err := q.UnquoteBinary(quoted)
+			if err != nil {
+				t.Fatalf("UnquoteBinary() = _, %v; want nil", err)
+			}
+			if diff := cmp.Diff(td.Input, unquoted); diff != "" {
+				t.Errorf("UnquoteBinary() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Q     quote.Quoting
+		Input string
+		Spans []quote.Span
+	}{
+		{
+			Name:  "SingleQuote",
+			Q:     SingleQuote,
+			Input: `'a b'"'"'c'`,
+			Spans: []quote.Span{
+				{Start: 0, End: 1, Kind: quote.SpanOpenQuote},
+				{Start: 1, End: 4, Kind: quote.SpanLiteral, Value: "a b"},
+				{Start: 4, End: 5, Kind: quote.SpanCloseQuote},
+				{Start: 5, End: 6, Kind: quote.SpanOpenQuote},
+				{Start: 6, End: 7, Kind: quote.SpanLiteral, Value: "'"},
+				{Start: 7, End: 8, Kind: quote.SpanCloseQuote},
+				{Start: 8, End: 9, Kind: quote.SpanOpenQuote},
+				{Start: 9, End: 10, Kind: quote.SpanLiteral, Value: "c"},
+				{Start: 10, End: 11, Kind: quote.SpanCloseQuote},
+			},
+		},
+		{
+			Name:  "DoubleQuote",
+			Q:     DoubleQuote,
+			Input: "\"a\\$b\\q\\\nc\"",
+			Spans: []quote.Span{
+				{Start: 0, End: 1, Kind: quote.SpanOpenQuote},
+				{Start: 1, End: 2, Kind: quote.SpanLiteral, Value: "a"},
+				{Start: 2, End: 4, Kind: quote.SpanEscape, Value: "$"},
+				{Start: 4, End: 7, Kind: quote.SpanLiteral, Value: `b\q`},
+				{Start: 7, End: 9, Kind: quote.SpanLineContinuation},
+				{Start: 9, End: 10, Kind: quote.SpanLiteral, Value: "c"},
+				{Start: 10, End: 11, Kind: quote.SpanCloseQuote},
+			},
+		},
+		{
+			Name:  "ANSIC",
+			Q:     ANSIC,
+			Input: `$'ab\n\x41é\cA\q'`,
+			Spans: []quote.Span{
+				{Start: 0, End: 2, Kind: quote.SpanOpenQuote},
+				{Start: 2, End: 4, Kind: quote.SpanLiteral, Value: "ab"},
+				{Start: 4, End: 6, Kind: quote.SpanEscape, Value: "\n"},
+				{Start: 6, End: 10, Kind: quote.SpanEscape, Value: "A"},
+				{Start: 10, End: 12, Kind: quote.SpanLiteral, Value: "é"},
+				{Start: 12, End: 15, Kind: quote.SpanEscape, Value: "\x01"},
+				{Start: 15, End: 17, Kind: quote.SpanLiteral, Value: `\q`},
+				{Start: 17, End: 18, Kind: quote.SpanCloseQuote},
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			spans, err := td.Q.(quote.Explainer).Explain(td.Input)
+			if err != nil {
+				t.Fatalf("Explain() = _, %v; want nil", err)
+			}
+			if diff := cmp.Diff(td.Spans, spans); diff != "" {
+				t.Errorf("Explain() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
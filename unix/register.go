@@ -0,0 +1,23 @@
+package unix
+
+import "github.com/sergeymakinen/go-quote"
+
+func init() {
+	for _, d := range []quote.Dialect{
+		{Name: "unix.SingleQuote", Quoting: SingleQuote, Newline: true, Binary: true, InvalidUTF8: true},
+		{Name: "unix.DoubleQuote", Quoting: DoubleQuote, Newline: true, Binary: true, InvalidUTF8: true},
+		{Name: "unix.ANSIC", Quoting: ANSIC, Newline: true, Binary: true, InvalidUTF8: true},
+	} {
+		quote.Register(d)
+	}
+	for alias, name := range map[string]string{
+		"sh":   "unix.SingleQuote",
+		"bash": "unix.ANSIC",
+		"ksh":  "unix.ANSIC",
+		"zsh":  "unix.ANSIC",
+	} {
+		d, _ := quote.Lookup(name)
+		d.Name = alias
+		quote.Register(d)
+	}
+}
@@ -0,0 +1,33 @@
+package windows
+
+import (
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+)
+
+var appendTests = []testutil.AppendTest{
+	{Name: "Argv", Q: Argv.(quote.Appender), Invalid: "a"},
+	{Name: "Cmd", Q: Cmd.(quote.Appender)},
+	{Name: "Msiexec", Q: Msiexec.(quote.Appender), Invalid: "a"},
+	{Name: "PSSingleQuote", Q: PSSingleQuote.(quote.Appender), Invalid: "a"},
+	{Name: "PSDoubleQuote", Q: PSDoubleQuote.(quote.Appender), Invalid: "a"},
+	{Name: "PwshDoubleQuote", Q: PwshDoubleQuote.(quote.Appender), Invalid: "a"},
+}
+
+func TestAppendQuote_AppendUnquote(t *testing.T) {
+	testutil.TestAppend(t, appendTests)
+}
+
+func TestAppendQuote_AppendUnquote_Allocs(t *testing.T) {
+	testutil.TestAppendAllocs(t, appendTests)
+}
+
+func BenchmarkAppendQuote(b *testing.B) {
+	testutil.BenchmarkAppendQuote(b, appendTests)
+}
+
+func BenchmarkAppendUnquote(b *testing.B) {
+	testutil.BenchmarkAppendUnquote(b, appendTests)
+}
@@ -3,97 +3,206 @@ package windows
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
 )
 
 const argvUnsafeChars = "\t \""
 
+var (
+	argvUnsafe = quoteutil.NewASCIISet(argvUnsafeChars)
+
+	// argvEscaped are characters that may need escaping with backslashes by Argv.
+	argvEscaped = quoteutil.NewASCIISet("\"\\")
+)
+
 type argv struct{}
 
+func (argv) String() string { return "windows.Argv" }
+
 func (argv) MustQuote(s string) bool {
-	return strings.ContainsAny(s, argvUnsafeChars)
+	return argvUnsafe.Index(s) >= 0
 }
 
-func (argv) Quote(s string) string {
-	var (
-		buf     strings.Builder
-		slashes int
-	)
-	for i := 0; i < len(s); i++ {
-		switch s[i] {
+func (q argv) Quote(s string) string {
+	return string(q.AppendQuote(make([]byte, 0, len(s)+2), s))
+}
+
+func (q argv) QuoteStrict(s string) (string, error) {
+	return quoteStrict(q, s, false)
+}
+
+func (argv) AppendQuote(dst []byte, s string) []byte {
+	var q argvQuoter
+	dst, _, _ = q.Transform(dst, s, true)
+	return dst
+}
+
+func (argv) NewQuoter() quote.Transformer {
+	return &argvQuoter{}
+}
+
+func (q argv) Unquote(s string) (string, error) {
+	b, err := q.AppendUnquote(make([]byte, 0, len(s)), s)
+	return string(b), err
+}
+
+func (q argv) AppendUnquote(dst []byte, s string) ([]byte, error) {
+	return quoteutil.AppendUnquote(dst, s, q)
+}
+
+func (argv) NewUnquoter() quote.Transformer {
+	return &argvUnquoter{}
+}
+
+func (q argv) Explain(s string) ([]quote.Span, error) {
+	u := argvUnquoter{spans: &quoteutil.SpanList{}}
+	b, _, err := u.unquote(nil, s, quoteutil.UnquoteAll, true)
+	return u.spans.Explain(b, err, q, s)
+}
+
+func (q argv) Split(s string) ([]string, error) {
+	return quoteutil.Split(s, q, isBlank)
+}
+
+func (q argv) QuotedPrefix(s string) (string, string, error) {
+	return quoteutil.QuotedPrefix(s, q)
+}
+
+func (argv) UnquoteMode(dst []byte, s string, mode quoteutil.Mode) ([]byte, int, error) {
+	var u argvUnquoter
+	return u.unquote(dst, s, mode, true)
+}
+
+type argvQuoter struct {
+	started bool
+	slashes int
+}
+
+func (q *argvQuoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	if !q.started {
+		dst = append(dst, '"')
+		q.started = true
+	}
+	for i := 0; i < len(src); i++ {
+		switch src[i] {
 		case '"':
-			for slashes++; slashes > 0; slashes-- {
-				buf.WriteByte('\\')
+			for q.slashes++; q.slashes > 0; q.slashes-- {
+				dst = append(dst, '\\')
 			}
-			buf.WriteByte(s[i])
+			dst = append(dst, src[i])
 		case '\\':
-			slashes++
-			buf.WriteByte(s[i])
+			q.slashes++
+			dst = append(dst, src[i])
 		default:
-			slashes = 0
-			buf.WriteByte(s[i])
+			// Runs of characters other than double quotes and backslashes are copied as is.
+			j := argvEscaped.Index(src[i:])
+			if j < 0 {
+				j = len(src) - i
+			}
+			q.slashes = 0
+			dst = append(dst, src[i:i+j]...)
+			i += j - 1
 		}
 	}
-	for ; slashes > 0; slashes-- {
-		buf.WriteByte('\\')
+	if atEOF {
+		for ; q.slashes > 0; q.slashes-- {
+			dst = append(dst, '\\')
+		}
+		dst = append(dst, '"')
 	}
-	return `"` + buf.String() + `"`
+	return dst, len(src), nil
 }
 
-func (a argv) Unquote(s string) (string, error) {
-	var (
-		buf     strings.Builder
-		inQuote bool
-		slashes int
-	)
-	for i := 0; i < len(s); i++ {
+type argvUnquoter struct {
+	inQuote bool
+	slashes int
+	spans   *quoteutil.SpanList
+}
+
+func (u *argvUnquoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	return u.unquote(dst, src, quoteutil.UnquoteAll, atEOF)
+}
+
+func (u *argvUnquoter) unquote(dst []byte, s string, mode quoteutil.Mode, atEOF bool) ([]byte, int, error) {
+	i := 0
+loop:
+	for ; i < len(s); i++ {
+		n := len(dst)
 		switch s[i] {
 		case '"':
-			if slashes > 0 {
-				if slashes%2 == 0 {
-					for ; slashes > 0; slashes -= 2 {
-						buf.WriteByte('\\')
+			if u.slashes > 0 {
+				start := i - u.slashes
+				if u.slashes%2 == 0 {
+					for ; u.slashes > 0; u.slashes -= 2 {
+						dst = append(dst, '\\')
 					}
-					inQuote = !inQuote
+					u.spans.Add(quote.SpanEscape, start, i, n, len(dst))
+					u.inQuote = !u.inQuote
+					u.spans.AddQuote(u.inQuote, i, i+1, len(dst))
 				} else {
-					for slashes--; slashes > 0; slashes -= 2 {
-						buf.WriteByte('\\')
+					for u.slashes--; u.slashes > 0; u.slashes -= 2 {
+						dst = append(dst, '\\')
 					}
-					buf.WriteByte(s[i])
+					dst = append(dst, s[i])
+					u.spans.Add(quote.SpanEscape, start, i+1, n, len(dst))
 				}
 			} else {
-				inQuote = !inQuote
+				u.inQuote = !u.inQuote
+				u.spans.AddQuote(u.inQuote, i, i+1, n)
 			}
 		case '\\':
-			if !inQuote {
-				return "", &quote.SyntaxError{
-					Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
-					Offset: i + 1,
+			if !u.inQuote {
+				if mode == quoteutil.UnquoteAll || mode == quoteutil.UnquotePrefix && i == 0 {
+					return nil, 0, &quote.SyntaxError{
+						Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
+						Kind:   quote.ErrOutsideQuotes,
+						Offset: i,
+					}
+				}
+				if mode == quoteutil.UnquotePrefix {
+					break loop
 				}
 			}
-			slashes++
+			u.slashes++
 		default:
-			if !inQuote {
-				return "", &quote.SyntaxError{
-					Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
-					Offset: i + 1,
+			if !u.inQuote {
+				if mode == quoteutil.UnquoteAll || mode == quoteutil.UnquotePrefix && i == 0 {
+					return nil, 0, &quote.SyntaxError{
+						Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
+						Kind:   quote.ErrOutsideQuotes,
+						Offset: i,
+					}
+				}
+				if mode == quoteutil.UnquotePrefix || isBlank(s[i]) {
+					break loop
 				}
 			}
-			for ; slashes > 0; slashes-- {
-				buf.WriteByte('\\')
+			start := i - u.slashes
+			for ; u.slashes > 0; u.slashes-- {
+				dst = append(dst, '\\')
 			}
-			buf.WriteByte(s[i])
+			dst = append(dst, s[i])
+			u.spans.Add(quote.SpanLiteral, start, i+1, n, len(dst))
 		}
 	}
-	if inQuote {
-		return "", &quote.SyntaxError{
+	if !atEOF {
+		return dst, i, nil
+	}
+	if u.inQuote {
+		return nil, 0, &quote.SyntaxError{
 			Msg:    "unterminated quoted string",
+			Kind:   quote.ErrUnterminated,
 			Offset: len(s),
 		}
 	}
-	return buf.String(), nil
+	n, start := len(dst), i-u.slashes
+	for ; u.slashes > 0; u.slashes-- {
+		dst = append(dst, '\\')
+	}
+	u.spans.Add(quote.SpanLiteral, start, i, n, len(dst))
+	return dst, i, nil
 }
 
 // Argv quotes and unquotes strings, surrounded by double quotes ("…"),
@@ -76,32 +76,44 @@ func TestArgv_Unquote_ShouldFail(t *testing.T) {
 			Name:  "unterminated string #1",
 			Input: `"a`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated quoted string",
-				Offset: 2,
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "windows.Argv",
+				Input:   `"a`,
+				Offset:  2,
 			},
 		},
 		{
 			Name:  "unterminated string #2",
 			Input: `\`,
 			Err: &quote.SyntaxError{
-				Msg:    `character U+005C '\' outside of quoted string`,
-				Offset: 1,
+				Msg:     `character U+005C '\' outside of quoted string`,
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.Argv",
+				Input:   `\`,
+				Offset:  0,
 			},
 		},
 		{
 			Name:  "char outside of string",
 			Input: "a",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 1,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.Argv",
+				Input:   "a",
+				Offset:  0,
 			},
 		},
 		{
 			Name:  "char after string",
 			Input: `"a"a`,
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 4,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.Argv",
+				Input:   `"a"a`,
+				Offset:  3,
 			},
 		},
 	}
@@ -127,3 +139,24 @@ func TestArgv_Quote_Unquote_InputTests(t *testing.T) {
 		})
 	}
 }
+
+func TestArgv_Split(t *testing.T) {
+	args, err := Argv.(quote.Splitter).Split(`callme.exe "a b" c\d "e\"f" g\\"h i"`)
+	if err != nil {
+		t.Fatalf("Argv.Split() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff([]string{"callme.exe", "a b", `c\d`, `e"f`, `g\h i`}, args); diff != "" {
+		t.Errorf("Argv.Split() mismatch (-want +got):\n%s", diff)
+	}
+	_, err = Argv.(quote.Splitter).Split(`a "b`)
+	expected := &quote.SyntaxError{
+		Msg:     "unterminated quoted string",
+		Kind:    quote.ErrUnterminated,
+		Dialect: "windows.Argv",
+		Input:   `a "b`,
+		Offset:  4,
+	}
+	if diff := cmp.Diff(expected, err); diff != "" {
+		t.Errorf("Argv.Split() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -1,69 +1,139 @@
 package windows
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
 )
 
+const cmdUnsafeChars = "!\"&'+,;<=>[]^`{}~"
+
 var (
-	cmdUnsafeChars   = "!\"&'+,;<=>[]^`{}~"
-	cmdQuoteReplacer = strings.NewReplacer(
-		"\t", "^\t",
-		" ", "^ ",
-		"!", "^!",
-		`"`, `^"`,
-		"&", "^&",
-		"'", "^'",
-		"+", "^+",
-		",", "^,",
-		";", "^;",
-		"<", "^<",
-		"=", "^=",
-		">", "^>",
-		"[", "^[",
-		"]", "^]",
-		"^", "^^",
-		"`", "^`",
-		"{", "^{",
-		"}", "^}",
-		"~", "^~",
-	)
-	cmdUnquoteReplacer = strings.NewReplacer(
-		"^\t", "\t",
-		"^ ", " ",
-		"^!", "!",
-		`^"`, `"`,
-		"^&", "&",
-		"^'", "'",
-		"^+", "+",
-		"^,", ",",
-		"^;", ";",
-		"^<", "<",
-		"^=", "=",
-		"^>", ">",
-		"^[", "[",
-		"^]", "]",
-		"^^", "^",
-		"^`", "`",
-		"^{", "{",
-		"^}", "}",
-		"^~", "~",
-	)
+	cmdUnsafe = quoteutil.NewASCIISet(cmdUnsafeChars)
+
+	// cmdSpecial are characters escaped with a caret (^) by Cmd.
+	cmdSpecial = quoteutil.NewASCIISet(cmdUnsafeChars + " \t")
 )
 
+// isCmdSpecial reports whether c is escaped with a caret (^) by Cmd.
+func isCmdSpecial(c byte) bool {
+	return cmdSpecial.Contains(c)
+}
+
 type cmd struct{}
 
+func (cmd) String() string { return "windows.Cmd" }
+
 func (cmd) MustQuote(s string) bool {
-	return strings.ContainsAny(s, cmdUnsafeChars)
+	return cmdUnsafe.Index(s) >= 0
+}
+
+func (q cmd) Quote(s string) string {
+	return string(q.AppendQuote(make([]byte, 0, len(s)), s))
+}
+
+func (q cmd) QuoteStrict(s string) (string, error) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\n', '\r':
+			return "", quoteutil.Unrepresentable(q.String(), s, i, fmt.Sprintf("unsupported character %#U", s[i]))
+		case '%':
+			// cmd.exe expands variables even if percent signs are escaped.
+			if j := strings.IndexByte(s[i+1:], '%'); j > 0 {
+				return "", quoteutil.Unrepresentable(q.String(), s, i, "variable reference "+s[i:i+j+2])
+			}
+		}
+	}
+	return quoteStrict(q, s, false)
+}
+
+func (cmd) AppendQuote(dst []byte, s string) []byte {
+	dst, _, _ = cmdQuoter{}.Transform(dst, s, true)
+	return dst
+}
+
+func (cmd) NewQuoter() quote.Transformer {
+	return cmdQuoter{}
+}
+
+func (q cmd) Unquote(s string) (string, error) {
+	b, err := q.AppendUnquote(make([]byte, 0, len(s)), s)
+	return string(b), err
+}
+
+func (q cmd) AppendUnquote(dst []byte, s string) ([]byte, error) {
+	return quoteutil.AppendUnquote(dst, s, q)
 }
 
-func (cmd) Quote(s string) string {
-	return cmdQuoteReplacer.Replace(s)
+func (cmd) NewUnquoter() quote.Transformer {
+	return cmdUnquoter{}
 }
 
-func (cmd) Unquote(s string) (string, error) {
-	return cmdUnquoteReplacer.Replace(s), nil
+func (q cmd) Explain(s string) ([]quote.Span, error) {
+	u := cmdUnquoter{spans: &quoteutil.SpanList{}}
+	b, _, err := u.unquote(nil, s, quoteutil.UnquoteAll, true)
+	return u.spans.Explain(b, err, q, s)
+}
+
+func (q cmd) Split(s string) ([]string, error) {
+	return quoteutil.Split(s, q, isBlank)
+}
+
+func (q cmd) QuotedPrefix(s string) (string, string, error) {
+	return quoteutil.QuotedPrefix(s, q)
+}
+
+func (cmd) UnquoteMode(dst []byte, s string, mode quoteutil.Mode) ([]byte, int, error) {
+	return cmdUnquoter{}.unquote(dst, s, mode, true)
+}
+
+type cmdQuoter struct{}
+
+func (cmdQuoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	n := len(src)
+	for {
+		i := cmdSpecial.Index(src)
+		if i < 0 {
+			break
+		}
+		dst = append(dst, src[:i]...)
+		dst = append(dst, '^', src[i])
+		src = src[i+1:]
+	}
+	dst = append(dst, src...)
+	return dst, n, nil
+}
+
+type cmdUnquoter struct {
+	spans *quoteutil.SpanList
+}
+
+func (u cmdUnquoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	return u.unquote(dst, src, quoteutil.UnquoteAll, atEOF)
+}
+
+func (u cmdUnquoter) unquote(dst []byte, s string, mode quoteutil.Mode, atEOF bool) ([]byte, int, error) {
+	i := 0
+	for ; i < len(s); i++ {
+		if s[i] == '^' && i+1 == len(s) && !atEOF {
+			break
+		}
+		start, n, kind := i, len(dst), quote.SpanLiteral
+		if s[i] == '^' && i+1 < len(s) {
+			if i++; isCmdSpecial(s[i]) {
+				kind = quote.SpanEscape
+			} else {
+				dst = append(dst, '^')
+			}
+		} else if mode != quoteutil.UnquoteAll && isBlank(s[i]) {
+			break
+		}
+		dst = append(dst, s[i])
+		u.spans.Add(kind, start, i+1, n, len(dst))
+	}
+	return dst, i, nil
 }
 
 // Cmd quotes and unquotes strings containing characters special to the Windows command interpreter (cmd.exe).
@@ -76,6 +146,12 @@ func (cmd) Unquote(s string) (string, error) {
 //
 //  a b:^"c d^" ^'e^'^'f^'  ^"g\^"^"
 //
+// Variable references, like %PATH%, are still expanded by cmd.exe after quoting,
+// so QuoteStrict rejects them along with newlines.
+//
+// As Cmd has no quotes to delimit strings, QuotedPrefix takes the leading word
+// up to the first unescaped space or tab as the quoted string.
+//
 // See https://docs.microsoft.com/en-us/archive/blogs/twistylittlepassagesallalike/everyone-quotes-command-line-arguments-the-wrong-way
 // for details.
 var Cmd quote.Quoting = cmd{}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
 	"github.com/sergeymakinen/go-quote/internal/testutil"
 )
 
@@ -75,3 +77,13 @@ func TestCmd_Quote_Unquote_InputTests(t *testing.T) {
 		})
 	}
 }
+
+func TestCmd_Split(t *testing.T) {
+	args, err := Cmd.(quote.Splitter).Split(`echo a^ b^&c  ^^ d^`)
+	if err != nil {
+		t.Fatalf("Cmd.Split() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff([]string{"echo", "a b&c", "^", "d^"}, args); diff != "" {
+		t.Errorf("Cmd.Split() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,156 @@
+package windows
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
+)
+
+// psWildcards are characters escaped with a backtick (`) in PowerShell wildcard patterns.
+var psWildcards = quoteutil.NewASCIISet("*?[]`")
+
+// contextQuoting is a quoting of this package.
+type contextQuoting interface {
+	quote.Quoting
+	String() string
+	QuoteStrict(s string) (string, error)
+}
+
+func unsupportedContext(q contextQuoting, c quote.Context, s string) error {
+	return quoteutil.Unrepresentable(q.String(), s, 0, "unsupported context "+c.String())
+}
+
+// quoteWord quotes s with q if it's empty or must be quoted.
+func quoteWord(q contextQuoting, s string) (string, error) {
+	if s != "" && !q.MustQuote(s) {
+		return s, nil
+	}
+	return q.QuoteStrict(s)
+}
+
+// quoteArgContext quotes s with q, quoting command-line arguments, such that it appears correctly in context c.
+// comments reports whether the program parsing s has comments, like rem … for cmd.exe.
+func quoteArgContext(q contextQuoting, c quote.Context, s string, comments bool) (string, error) {
+	switch {
+	case c == quote.ContextArgument:
+		return quoteWord(q, s)
+	case c == quote.ContextAssignment:
+		if s == "" {
+			return s, nil
+		}
+		return quoteWord(q, s)
+	case c == quote.ContextComment && comments:
+		return quoteComment(q, s)
+	default:
+		return "", unsupportedContext(q, c, s)
+	}
+}
+
+// quoteComment returns s unless it contains line breaks or can't be quoted with q.
+func quoteComment(q contextQuoting, s string) (string, error) {
+	if i := strings.IndexAny(s, "\n\r"); i >= 0 {
+		return "", quoteutil.Unrepresentable(q.String(), s, i, fmt.Sprintf("unsupported character %#U", s[i]))
+	}
+	if err := checkStrict(q.String(), s, false); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// quotePSContext quotes s with q, quoting PowerShell strings, such that it appears correctly in context c.
+// Here-strings (@'…'@ or @"…"@) are quoted with heredoc.
+func quotePSContext(q contextQuoting, c quote.Context, s string, heredoc func(s string) (string, error)) (string, error) {
+	switch c {
+	case quote.ContextArgument:
+		return quoteWord(q, s)
+	case quote.ContextAssignment:
+		// Values are parsed in expression mode, where bare words are commands.
+		return q.QuoteStrict(s)
+	case quote.ContextHeredoc:
+		return heredoc(s)
+	case quote.ContextComment:
+		return quoteComment(q, s)
+	case quote.ContextPattern:
+		if psWildcards.Index(s) < 0 {
+			return q.QuoteStrict(s)
+		}
+		b := make([]byte, 0, len(s)+2)
+		for i := 0; i < len(s); i++ {
+			if psWildcards.Contains(s[i]) {
+				b = append(b, '`')
+			}
+			b = append(b, s[i])
+		}
+		return q.QuoteStrict(string(b))
+	default:
+		return "", unsupportedContext(q, c, s)
+	}
+}
+
+// isLineStart reports whether the byte at index i starts a line of s.
+func isLineStart(s string, i int) bool {
+	return i == 0 || s[i-1] == '\n' || s[i-1] == '\r'
+}
+
+// psSingleHeredoc returns s unless it can't be placed in a single-quoted here-string,
+// ended by a line starting with a single quote followed by @.
+func psSingleHeredoc(q contextQuoting, s string) (string, error) {
+	for i, r := range s {
+		if isPSSingleQuote(r) && isLineStart(s, i) && strings.HasPrefix(s[i+utf8.RuneLen(r):], "@") {
+			return "", quoteutil.Unrepresentable(q.String(), s, i, "here-string terminator")
+		}
+	}
+	if err := checkStrict(q.String(), s, true); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// psDoubleHeredoc returns s with characters special in double-quoted here-strings escaped
+// with a backtick (`): dollar signs, backticks and double quotes starting lines, which would end it.
+func psDoubleHeredoc(q contextQuoting, s string, allowNUL bool) (string, error) {
+	if err := checkStrict(q.String(), s, allowNUL); err != nil {
+		return "", err
+	}
+	b := make([]byte, 0, len(s)+2)
+	for i, r := range s {
+		if r == '$' || r == '`' || isPSDoubleQuote(r) && isLineStart(s, i) {
+			b = append(b, '`')
+		}
+		b = utf8.AppendRune(b, r)
+	}
+	return string(b), nil
+}
+
+func (q argv) QuoteContext(c quote.Context, s string) (string, error) {
+	return quoteArgContext(q, c, s, false)
+}
+
+func (q cmd) QuoteContext(c quote.Context, s string) (string, error) {
+	return quoteArgContext(q, c, s, true)
+}
+
+func (q msiexec) QuoteContext(c quote.Context, s string) (string, error) {
+	return quoteArgContext(q, c, s, false)
+}
+
+func (q psSingleQuote) QuoteContext(c quote.Context, s string) (string, error) {
+	return quotePSContext(q, c, s, func(s string) (string, error) {
+		return psSingleHeredoc(q, s)
+	})
+}
+
+func (q psDoubleQuote) QuoteContext(c quote.Context, s string) (string, error) {
+	return quotePSContext(q, c, s, func(s string) (string, error) {
+		return psDoubleHeredoc(q, s, false)
+	})
+}
+
+func (q pwshDoubleQuote) QuoteContext(c quote.Context, s string) (string, error) {
+	return quotePSContext(q, c, s, func(s string) (string, error) {
+		return psDoubleHeredoc(q, s, true)
+	})
+}
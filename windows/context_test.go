@@ -0,0 +1,241 @@
+package windows
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+)
+
+func TestQuoteContext(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Q       quote.Quoting
+		Context quote.Context
+		Input   string
+		Output  string
+		Err     error
+	}{
+		{
+			Name:    "Argv;argument",
+			Q:       Argv,
+			Context: quote.ContextArgument,
+			Input:   "a b",
+			Output:  `"a b"`,
+		},
+		{
+			Name:    "Argv;assignment;empty",
+			Q:       Argv,
+			Context: quote.ContextAssignment,
+			Input:   "",
+			Output:  "",
+		},
+		{
+			Name:    "Msiexec;assignment",
+			Q:       Msiexec,
+			Context: quote.ContextAssignment,
+			Input:   `C:\Program Files\App`,
+			Output:  `"C:\Program Files\App"`,
+		},
+		{
+			Name:    "Cmd;assignment",
+			Q:       Cmd,
+			Context: quote.ContextAssignment,
+			Input:   "a&b",
+			Output:  "a^&b",
+		},
+		{
+			Name:    "Cmd;comment",
+			Q:       Cmd,
+			Context: quote.ContextComment,
+			Input:   "a & b",
+			Output:  "a & b",
+		},
+		{
+			Name:    "Cmd;comment;newline",
+			Q:       Cmd,
+			Context: quote.ContextComment,
+			Input:   "a\r\nb",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+000D",
+				Dialect: "windows.Cmd",
+				Input:   "a\r\nb",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "Argv;comment",
+			Q:       Argv,
+			Context: quote.ContextComment,
+			Input:   "a",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported context comment",
+				Dialect: "windows.Argv",
+				Input:   "a",
+			},
+		},
+		{
+			Name:    "PSSingleQuote;argument",
+			Q:       PSSingleQuote,
+			Context: quote.ContextArgument,
+			Input:   "abc",
+			Output:  "abc",
+		},
+		{
+			Name:    "PSSingleQuote;assignment",
+			Q:       PSSingleQuote,
+			Context: quote.ContextAssignment,
+			Input:   "abc",
+			Output:  "'abc'",
+		},
+		{
+			Name:    "PSSingleQuote;pattern",
+			Q:       PSSingleQuote,
+			Context: quote.ContextPattern,
+			Input:   "a*[b]?`",
+			Output:  "'a`*`[b`]`?``'",
+		},
+		{
+			Name:    "PSDoubleQuote;pattern",
+			Q:       PSDoubleQuote,
+			Context: quote.ContextPattern,
+			Input:   "$a*",
+			Output:  "\"`$a``*\"",
+		},
+		{
+			Name:    "PSSingleQuote;heredoc",
+			Q:       PSSingleQuote,
+			Context: quote.ContextHeredoc,
+			Input:   "it's $a\n '@",
+			Output:  "it's $a\n '@",
+		},
+		{
+			Name:    "PSSingleQuote;heredoc;terminator",
+			Q:       PSSingleQuote,
+			Context: quote.ContextHeredoc,
+			Input:   "a\n'@",
+			Err: &quote.UnrepresentableError{
+				Msg:     "here-string terminator",
+				Dialect: "windows.PSSingleQuote",
+				Input:   "a\n'@",
+				Offset:  2,
+			},
+		},
+		{
+			Name:    "PSSingleQuote;heredoc;curly terminator",
+			Q:       PSSingleQuote,
+			Context: quote.ContextHeredoc,
+			Input:   "a\n’@",
+			Err: &quote.UnrepresentableError{
+				Msg:     "here-string terminator",
+				Dialect: "windows.PSSingleQuote",
+				Input:   "a\n’@",
+				Offset:  2,
+			},
+		},
+		{
+			Name:    "PSDoubleQuote;heredoc",
+			Q:       PSDoubleQuote,
+			Context: quote.ContextHeredoc,
+			Input:   "\"@ $a `b`\n\"@ \"c\"",
+			Output:  "`\"@ `$a ``b``\n`\"@ \"c\"",
+		},
+		{
+			Name:    "PSDoubleQuote;heredoc;curly quotes",
+			Q:       PSDoubleQuote,
+			Context: quote.ContextHeredoc,
+			Input:   "“@\n”@ „a”",
+			Output:  "`“@\n`”@ „a”",
+		},
+		{
+			Name:    "PSDoubleQuote;heredoc;NUL",
+			Q:       PSDoubleQuote,
+			Context: quote.ContextHeredoc,
+			Input:   "a\x00",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "a\x00",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "PwshDoubleQuote;heredoc;NUL",
+			Q:       PwshDoubleQuote,
+			Context: quote.ContextHeredoc,
+			Input:   "a\x00",
+			Output:  "a\x00",
+		},
+		{
+			Name:    "PwshDoubleQuote;comment",
+			Q:       PwshDoubleQuote,
+			Context: quote.ContextComment,
+			Input:   "a\xFF",
+			Err: &quote.UnrepresentableError{
+				Msg:     "invalid UTF-8 byte 0xff",
+				Dialect: "windows.PwshDoubleQuote",
+				Input:   "a\xFF",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "PSSingleQuote;parameter word",
+			Q:       PSSingleQuote,
+			Context: quote.ContextParameterWord,
+			Input:   "a",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported context parameter expansion word",
+				Dialect: "windows.PSSingleQuote",
+				Input:   "a",
+			},
+		},
+		{
+			Name:    "PSSingleQuote;assignment;display-safe",
+			Q:       NewPSSingleQuote(DisplaySafe()),
+			Context: quote.ContextAssignment,
+			Input:   "a\u200Bb",
+			Err: &quote.UnrepresentableError{
+				Msg:     "invisible character U+200B",
+				Dialect: "windows.PSSingleQuote",
+				Input:   "a\u200Bb",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "PwshDoubleQuote;assignment;display-safe",
+			Q:       NewPwshDoubleQuote(DisplaySafe()),
+			Context: quote.ContextAssignment,
+			Input:   "a\u200Bb",
+			Output:  "\"a`u{200B}b\"",
+		},
+		{
+			Name:    "PwshDoubleQuote;heredoc;display-safe",
+			Q:       NewPwshDoubleQuote(DisplaySafe()),
+			Context: quote.ContextHeredoc,
+			Input:   "a\u200Bb",
+			Err: &quote.UnrepresentableError{
+				Msg:     "invisible character U+200B",
+				Dialect: "windows.PwshDoubleQuote",
+				Input:   "a\u200Bb",
+				Offset:  1,
+			},
+		},
+		{
+			Name:    "Msiexec;argument;always quote",
+			Q:       NewMsiexec(AlwaysQuote()),
+			Context: quote.ContextArgument,
+			Input:   "a",
+			Output:  `"a"`,
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			s, err := quote.QuoteContext(td.Q, td.Context, td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuoteContext() mismatch (-want +got):\n%s", diff)
+			}
+			testutil.TestDiff(t, "QuoteContext()", td.Output, s)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package windows
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/quotetest"
+)
+
+func FuzzArgv(f *testing.F) {
+	fuzzQuoting(f, Argv, testutil.InputTests('"'), "")
+}
+
+func FuzzCmd(f *testing.F) {
+	fuzzQuoting(f, Cmd, testutil.InputTests('"'), "")
+}
+
+func FuzzMsiexec(f *testing.F) {
+	fuzzQuoting(f, Msiexec, testutil.InputTests('"'), "")
+}
+
+func FuzzPSSingleQuote(f *testing.F) {
+	fuzzQuoting(f, PSSingleQuote, testutil.InputTests('\'', '$', '`'), lookPowerShell("pwsh", "powershell"))
+}
+
+func FuzzPSDoubleQuote(f *testing.F) {
+	fuzzQuoting(f, PSDoubleQuote, testutil.InputTests('"', '$', '`'), lookPowerShell("powershell"))
+}
+
+func FuzzPwshDoubleQuote(f *testing.F) {
+	fuzzQuoting(f, PwshDoubleQuote, testutil.InputTests('"', '$', '`'), lookPowerShell("pwsh"))
+}
+
+// lookPowerShell returns the path of the first PowerShell executable found in names,
+// or an empty string if none is found.
+// PSDoubleQuote models Windows PowerShell 5.1 (powershell), which pwsh evaluates differently.
+func lookPowerShell(names ...string) string {
+	for _, name := range names {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// fuzzQuoting checks that strings quoted with q, unless q can't represent them, unquote back
+// and, if ps is not empty, that PowerShell at ps evaluates them back.
+func fuzzQuoting(f *testing.F, q quote.Quoting, inputs []quotetest.Input, ps string) {
+	for _, it := range inputs {
+		f.Add(it.Input)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		quoted, err := q.(quote.StrictQuoting).QuoteStrict(s)
+		if err != nil {
+			t.Skip(err)
+		}
+		unquoted, err := q.Unquote(quoted)
+		if err != nil {
+			t.Fatalf("%v.Unquote(%q) = _, %v; want nil", q, quoted, err)
+		}
+		if unquoted != s {
+			t.Fatalf("%v.Unquote(%q) = %q; want %q", q, quoted, unquoted, s)
+		}
+		if ps != "" {
+			fuzzPowerShell(t, ps, s, quoted)
+		}
+	})
+}
+
+// fuzzPowerShell checks that PowerShell at ps writes expected for quoted.
+func fuzzPowerShell(t *testing.T, ps, expected, quoted string) {
+	t.Helper()
+	script := "[Console]::OutputEncoding = [Text.UTF8Encoding]::new($false); [Console]::Out.Write(" + quoted + ")"
+	out, err := exec.Command(ps, "-NoProfile", "-NonInteractive", "-Command", script).Output()
+	if err != nil {
+		t.Fatalf("Cmd.Output() = _, %v; want nil\nCmd: %s -Command %q", err, ps, script)
+	}
+	if string(out) != expected {
+		t.Fatalf("%s wrote %q for %s; want %q", ps, out, quoted, expected)
+	}
+}
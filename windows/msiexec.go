@@ -5,52 +5,147 @@ import (
 	"strings"
 
 	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
 )
 
 type msiexec struct{}
 
+func (msiexec) String() string { return "windows.Msiexec" }
+
 func (msiexec) MustQuote(s string) bool {
-	return strings.ContainsAny(s, argvUnsafeChars)
+	return argvUnsafe.Index(s) >= 0
+}
+
+func (q msiexec) Quote(s string) string {
+	return string(q.AppendQuote(make([]byte, 0, len(s)+2), s))
+}
+
+func (q msiexec) QuoteStrict(s string) (string, error) {
+	return quoteStrict(q, s, false)
+}
+
+func (msiexec) AppendQuote(dst []byte, s string) []byte {
+	var q msiexecQuoter
+	dst, _, _ = q.Transform(dst, s, true)
+	return dst
+}
+
+func (msiexec) NewQuoter() quote.Transformer {
+	return &msiexecQuoter{}
 }
 
-func (msiexec) Quote(s string) string {
-	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+func (q msiexec) Unquote(s string) (string, error) {
+	b, err := q.AppendUnquote(make([]byte, 0, len(s)), s)
+	return string(b), err
 }
 
-func (msiexec) Unquote(s string) (string, error) {
-	var (
-		buf     strings.Builder
-		inQuote bool
-	)
-	for i := 0; i < len(s); i++ {
+func (q msiexec) AppendUnquote(dst []byte, s string) ([]byte, error) {
+	return quoteutil.AppendUnquote(dst, s, q)
+}
+
+func (msiexec) NewUnquoter() quote.Transformer {
+	return &msiexecUnquoter{}
+}
+
+func (q msiexec) Explain(s string) ([]quote.Span, error) {
+	u := msiexecUnquoter{spans: &quoteutil.SpanList{}}
+	b, _, err := u.unquote(nil, s, quoteutil.UnquoteAll, true)
+	return u.spans.Explain(b, err, q, s)
+}
+
+func (q msiexec) Split(s string) ([]string, error) {
+	return quoteutil.Split(s, q, isBlank)
+}
+
+func (q msiexec) QuotedPrefix(s string) (string, string, error) {
+	return quoteutil.QuotedPrefix(s, q)
+}
+
+func (msiexec) UnquoteMode(dst []byte, s string, mode quoteutil.Mode) ([]byte, int, error) {
+	var u msiexecUnquoter
+	return u.unquote(dst, s, mode, true)
+}
+
+type msiexecQuoter struct {
+	started bool
+}
+
+func (q *msiexecQuoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	if !q.started {
+		dst = append(dst, '"')
+		q.started = true
+	}
+	n := len(src)
+	for {
+		i := strings.IndexByte(src, '"')
+		if i < 0 {
+			break
+		}
+		dst = append(dst, src[:i+1]...)
+		dst = append(dst, '"')
+		src = src[i+1:]
+	}
+	dst = append(dst, src...)
+	if atEOF {
+		dst = append(dst, '"')
+	}
+	return dst, n, nil
+}
+
+type msiexecUnquoter struct {
+	inQuote bool
+	spans   *quoteutil.SpanList
+}
+
+func (u *msiexecUnquoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	return u.unquote(dst, src, quoteutil.UnquoteAll, atEOF)
+}
+
+func (u *msiexecUnquoter) unquote(dst []byte, s string, mode quoteutil.Mode, atEOF bool) ([]byte, int, error) {
+	i := 0
+	for ; i < len(s); i++ {
+		n := len(dst)
 		if s[i] == '"' {
-			if !inQuote {
-				inQuote = true
+			if !u.inQuote {
+				u.inQuote = true
+				u.spans.Add(quote.SpanOpenQuote, i, i+1, n, n)
 			} else {
 				if i+1 < len(s) && s[i+1] == '"' {
-					buf.WriteByte('"')
+					dst = append(dst, '"')
 					i++
+					u.spans.Add(quote.SpanEscape, i-1, i+1, n, len(dst))
+				} else if i+1 == len(s) && !atEOF {
+					break
 				} else {
-					inQuote = false
+					u.inQuote = false
+					u.spans.Add(quote.SpanCloseQuote, i, i+1, n, n)
 				}
 			}
 			continue
 		}
-		if !inQuote {
-			return "", &quote.SyntaxError{
-				Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
-				Offset: i + 1,
+		if !u.inQuote {
+			if mode == quoteutil.UnquoteAll || mode == quoteutil.UnquotePrefix && i == 0 {
+				return nil, 0, &quote.SyntaxError{
+					Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
+					Kind:   quote.ErrOutsideQuotes,
+					Offset: i,
+				}
+			}
+			if mode == quoteutil.UnquotePrefix || isBlank(s[i]) {
+				break
 			}
 		}
-		buf.WriteByte(s[i])
+		dst = append(dst, s[i])
+		u.spans.Add(quote.SpanLiteral, i, i+1, n, len(dst))
 	}
-	if inQuote {
-		return "", &quote.SyntaxError{
+	if atEOF && u.inQuote {
+		return nil, 0, &quote.SyntaxError{
 			Msg:    "unterminated quoted string",
+			Kind:   quote.ErrUnterminated,
 			Offset: len(s),
 		}
 	}
-	return buf.String(), nil
+	return dst, i, nil
 }
 
 // Msiexec quotes and unquotes strings, surrounded by double quotes ("…")
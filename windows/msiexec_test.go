@@ -53,32 +53,44 @@ func TestMsiexec_Unquote_ShouldFail(t *testing.T) {
 			Name:  "unterminated string #1",
 			Input: `"a`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated quoted string",
-				Offset: 2,
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "windows.Msiexec",
+				Input:   `"a`,
+				Offset:  2,
 			},
 		},
 		{
 			Name:  "unterminated string #2",
 			Input: `"a""`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated quoted string",
-				Offset: 4,
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "windows.Msiexec",
+				Input:   `"a""`,
+				Offset:  4,
 			},
 		},
 		{
 			Name:  "char outside of string",
 			Input: "a",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 1,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.Msiexec",
+				Input:   "a",
+				Offset:  0,
 			},
 		},
 		{
 			Name:  "char after string",
 			Input: `"a"a`,
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 4,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.Msiexec",
+				Input:   `"a"a`,
+				Offset:  3,
 			},
 		},
 	}
@@ -104,3 +116,24 @@ func TestMsiexec_Quote_Unquote_InputTests(t *testing.T) {
 		})
 	}
 }
+
+func TestMsiexec_Split(t *testing.T) {
+	args, err := Msiexec.(quote.Splitter).Split(`/i "a ""b""" PROP="c d"`)
+	if err != nil {
+		t.Fatalf("Msiexec.Split() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff([]string{"/i", `a "b"`, "PROP=c d"}, args); diff != "" {
+		t.Errorf("Msiexec.Split() mismatch (-want +got):\n%s", diff)
+	}
+	_, err = Msiexec.(quote.Splitter).Split(`a "b`)
+	expected := &quote.SyntaxError{
+		Msg:     "unterminated quoted string",
+		Kind:    quote.ErrUnterminated,
+		Dialect: "windows.Msiexec",
+		Input:   `a "b`,
+		Offset:  4,
+	}
+	if diff := cmp.Diff(expected, err); diff != "" {
+		t.Errorf("Msiexec.Split() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,240 @@
+package windows
+
+import (
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
+)
+
+// Option customizes a quoting returned by a constructor, like NewArgv.
+// Options other than DisplaySafe only change what MustQuote reports:
+// strings are still quoted and unquoted the same way.
+type Option func(*options)
+
+type options = quoteutil.Options
+
+// SafeChars makes MustQuote treat chars as safe, like "=" and ":".
+// Making characters special to the program or shell safe breaks command lines joined with quote.Join.
+func SafeChars(chars string) Option {
+	return func(o *options) { o.Safe += chars }
+}
+
+// UnsafeChars makes MustQuote treat chars as unsafe, so strings containing them are quoted.
+func UnsafeChars(chars string) Option {
+	return func(o *options) { o.Unsafe += chars }
+}
+
+// AlwaysQuote makes MustQuote report true for every string, so quote.Join quotes every argument.
+func AlwaysQuote() Option {
+	return func(o *options) { o.Always = true }
+}
+
+// DisplaySafe makes quoted strings display as what they are, protecting against "Trojan Source" attacks:
+// PwshDoubleQuote escapes bidirectional controls and invisible characters, reported by quote.Suspicious,
+// and QuoteStrict of other quotings fails on them, as they can't be escaped.
+// MustQuote reports true for strings with such characters.
+func DisplaySafe() Option {
+	return func(o *options) { o.DisplaySafe = true }
+}
+
+type customArgv struct {
+	argv
+	opts options
+}
+
+func (q customArgv) MustQuote(s string) bool {
+	return q.opts.MustQuote(s, q.argv.MustQuote)
+}
+
+func (q customArgv) QuoteStrict(s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return q.argv.QuoteStrict(s)
+}
+
+func (q customArgv) QuoteContext(c quote.Context, s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return quoteArgContext(q, c, s, false)
+}
+
+// NewArgv returns Argv customized with opts.
+func NewArgv(opts ...Option) quote.Quoting {
+	return customArgv{opts: quoteutil.NewOptions(opts)}
+}
+
+type customCmd struct {
+	cmd
+	opts options
+}
+
+func (q customCmd) MustQuote(s string) bool {
+	return q.opts.MustQuote(s, q.cmd.MustQuote)
+}
+
+func (q customCmd) QuoteStrict(s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return q.cmd.QuoteStrict(s)
+}
+
+func (q customCmd) QuoteContext(c quote.Context, s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return quoteArgContext(q, c, s, true)
+}
+
+// NewCmd returns Cmd customized with opts.
+func NewCmd(opts ...Option) quote.Quoting {
+	return customCmd{opts: quoteutil.NewOptions(opts)}
+}
+
+type customMsiexec struct {
+	msiexec
+	opts options
+}
+
+func (q customMsiexec) MustQuote(s string) bool {
+	return q.opts.MustQuote(s, q.msiexec.MustQuote)
+}
+
+func (q customMsiexec) QuoteStrict(s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return q.msiexec.QuoteStrict(s)
+}
+
+func (q customMsiexec) QuoteContext(c quote.Context, s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return quoteArgContext(q, c, s, false)
+}
+
+// NewMsiexec returns Msiexec customized with opts.
+func NewMsiexec(opts ...Option) quote.Quoting {
+	return customMsiexec{opts: quoteutil.NewOptions(opts)}
+}
+
+type customPSSingleQuote struct {
+	psSingleQuote
+	opts options
+}
+
+func (q customPSSingleQuote) MustQuote(s string) bool {
+	return q.opts.MustQuote(s, q.psSingleQuote.MustQuote)
+}
+
+func (q customPSSingleQuote) QuoteStrict(s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return q.psSingleQuote.QuoteStrict(s)
+}
+
+func (q customPSSingleQuote) QuoteBinaryStrict(b []byte) (string, error) {
+	return q.QuoteStrict(string(b))
+}
+
+func (q customPSSingleQuote) QuoteContext(c quote.Context, s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return quotePSContext(q, c, s, func(s string) (string, error) {
+		return psSingleHeredoc(q, s)
+	})
+}
+
+// NewPSSingleQuote returns PSSingleQuote customized with opts.
+func NewPSSingleQuote(opts ...Option) quote.Quoting {
+	return customPSSingleQuote{opts: quoteutil.NewOptions(opts)}
+}
+
+type customPSDoubleQuote struct {
+	psDoubleQuote
+	opts options
+}
+
+func (q customPSDoubleQuote) MustQuote(s string) bool {
+	return q.opts.MustQuote(s, q.psDoubleQuote.MustQuote)
+}
+
+func (q customPSDoubleQuote) QuoteStrict(s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return q.psDoubleQuote.QuoteStrict(s)
+}
+
+func (q customPSDoubleQuote) QuoteBinaryStrict(b []byte) (string, error) {
+	return q.QuoteStrict(string(b))
+}
+
+func (q customPSDoubleQuote) QuoteContext(c quote.Context, s string) (string, error) {
+	if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+		return "", err
+	}
+	return quotePSContext(q, c, s, func(s string) (string, error) {
+		return psDoubleHeredoc(q, s, false)
+	})
+}
+
+// NewPSDoubleQuote returns PSDoubleQuote customized with opts.
+func NewPSDoubleQuote(opts ...Option) quote.Quoting {
+	return customPSDoubleQuote{opts: quoteutil.NewOptions(opts)}
+}
+
+type customPwshDoubleQuote struct {
+	pwshDoubleQuote
+	opts options
+}
+
+func (q customPwshDoubleQuote) MustQuote(s string) bool {
+	return q.opts.MustQuote(s, q.pwshDoubleQuote.MustQuote)
+}
+
+func (q customPwshDoubleQuote) Quote(s string) string {
+	return string(q.AppendQuote(make([]byte, 0, len(s)+2), s))
+}
+
+func (q customPwshDoubleQuote) QuoteStrict(s string) (string, error) {
+	return quoteStrict(q, s, true)
+}
+
+func (q customPwshDoubleQuote) QuoteBinary(b []byte) string {
+	return q.Quote(string(b))
+}
+
+func (q customPwshDoubleQuote) QuoteBinaryStrict(b []byte) (string, error) {
+	return q.QuoteStrict(string(b))
+}
+
+func (q customPwshDoubleQuote) AppendQuote(dst []byte, s string) []byte {
+	qr := psDoubleQuoter{pwsh: true, displaySafe: q.opts.DisplaySafe}
+	dst, _, _ = qr.Transform(dst, s, true)
+	return dst
+}
+
+func (q customPwshDoubleQuote) NewQuoter() quote.Transformer {
+	return &psDoubleQuoter{pwsh: true, displaySafe: q.opts.DisplaySafe}
+}
+
+func (q customPwshDoubleQuote) QuoteContext(c quote.Context, s string) (string, error) {
+	if c == quote.ContextHeredoc || c == quote.ContextComment {
+		if err := q.opts.CheckDisplaySafe(q.String(), s); err != nil {
+			return "", err
+		}
+	}
+	return quotePSContext(q, c, s, func(s string) (string, error) {
+		return psDoubleHeredoc(q, s, true)
+	})
+}
+
+// NewPwshDoubleQuote returns PwshDoubleQuote customized with opts.
+func NewPwshDoubleQuote(opts ...Option) quote.Quoting {
+	return customPwshDoubleQuote{opts: quoteutil.NewOptions(opts)}
+}
@@ -0,0 +1,183 @@
+package windows
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+	"github.com/sergeymakinen/go-quote/quotetest"
+)
+
+func TestOptions_MustQuote(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Q     quote.Quoting
+		Input string
+		Want  bool
+	}{
+		{
+			Name:  "no options",
+			Q:     NewCmd(),
+			Input: "a=b",
+			Want:  true,
+		},
+		{
+			Name:  "safe chars",
+			Q:     NewCmd(SafeChars("=")),
+			Input: "a=b",
+			Want:  false,
+		},
+		{
+			Name:  "safe chars;unsafe",
+			Q:     NewPSSingleQuote(SafeChars("$")),
+			Input: "$a b",
+			Want:  true,
+		},
+		{
+			Name:  "safe chars;curly quote",
+			Q:     NewPwshDoubleQuote(SafeChars("$")),
+			Input: "$a”",
+			Want:  true,
+		},
+		{
+			Name:  "unsafe chars",
+			Q:     NewArgv(UnsafeChars("/")),
+			Input: "/q",
+			Want:  true,
+		},
+		{
+			Name:  "always quote",
+			Q:     NewMsiexec(AlwaysQuote()),
+			Input: "a",
+			Want:  true,
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			if got := td.Q.MustQuote(td.Input); got != td.Want {
+				t.Errorf("MustQuote(%q) = %v; want %v", td.Input, got, td.Want)
+			}
+		})
+	}
+}
+
+func TestOptions_Join(t *testing.T) {
+	args := []string{"app.exe", "/q", "a b"}
+	testutil.TestDiff(t, "Join()", `"app.exe" "/q" "a b"`, quote.Join(NewArgv(AlwaysQuote()), args))
+	testutil.TestDiff(t, "Join()", `app.exe "/q" "a b"`, quote.Join(NewArgv(UnsafeChars("/")), args))
+}
+
+func TestOptions_Dialect(t *testing.T) {
+	tests := []struct {
+		Name string
+		Q    quote.Quoting
+		Opts quotetest.Options
+	}{
+		{
+			Name: "NewArgv",
+			Q:    NewArgv(SafeChars("=")),
+			Opts: quotetest.Options{Delim: '"'},
+		},
+		{
+			Name: "NewCmd",
+			Q:    NewCmd(SafeChars("=")),
+			Opts: quotetest.Options{Delim: '"'},
+		},
+		{
+			Name: "NewMsiexec",
+			Q:    NewMsiexec(SafeChars("=")),
+			Opts: quotetest.Options{Delim: '"'},
+		},
+		{
+			Name: "NewPSSingleQuote",
+			Q:    NewPSSingleQuote(SafeChars("=")),
+			Opts: quotetest.Options{Delim: '\'', UnsafeChars: []byte("$`")},
+		},
+		{
+			Name: "NewPSDoubleQuote",
+			Q:    NewPSDoubleQuote(SafeChars("=")),
+			Opts: quotetest.Options{Delim: '\'', UnsafeChars: []byte("$`")},
+		},
+		{
+			Name: "NewPwshDoubleQuote",
+			Q:    NewPwshDoubleQuote(SafeChars("=")),
+			Opts: quotetest.Options{Delim: '\'', UnsafeChars: []byte("$`")},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			quotetest.TestQuoting(t, td.Q, td.Opts)
+		})
+	}
+}
+
+func TestDisplaySafe(t *testing.T) {
+	const input = "a\u3164b\u202Ec"
+	tests := []struct {
+		Name   string
+		Q      quote.Quoting
+		Output string
+		Err    error
+	}{
+		{
+			Name:   "PwshDoubleQuote",
+			Q:      NewPwshDoubleQuote(),
+			Output: "\"a\u3164b`u{202E}c\"",
+		},
+		{
+			Name:   "PwshDoubleQuote;display-safe",
+			Q:      NewPwshDoubleQuote(DisplaySafe()),
+			Output: "\"a`u{3164}b`u{202E}c\"",
+		},
+		{
+			Name: "PSDoubleQuote;display-safe",
+			Q:    NewPSDoubleQuote(DisplaySafe()),
+			Err: &quote.UnrepresentableError{
+				Msg:     "invisible character U+3164",
+				Dialect: "windows.PSDoubleQuote",
+				Input:   input,
+				Offset:  1,
+			},
+		},
+		{
+			Name: "Argv;display-safe",
+			Q:    NewArgv(DisplaySafe()),
+			Err: &quote.UnrepresentableError{
+				Msg:     "invisible character U+3164",
+				Dialect: "windows.Argv",
+				Input:   input,
+				Offset:  1,
+			},
+		},
+		{
+			Name: "Cmd;display-safe",
+			Q:    NewCmd(DisplaySafe()),
+			Err: &quote.UnrepresentableError{
+				Msg:     "invisible character U+3164",
+				Dialect: "windows.Cmd",
+				Input:   input,
+				Offset:  1,
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			quoted, err := td.Q.(quote.StrictQuoting).QuoteStrict(input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuoteStrict() mismatch (-want +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			testutil.TestDiff(t, "QuoteStrict()", td.Output, quoted)
+			unquoted, err := td.Q.Unquote(quoted)
+			if err != nil {
+				t.Fatalf("Unquote() = _, %v; want nil", err)
+			}
+			testutil.TestDiff(t, "Unquote()", input, unquoted)
+		})
+	}
+	q := NewPwshDoubleQuote(DisplaySafe())
+	testutil.TestDiff(t, "Join()", "ls \"a`u{200B}b\"", quote.Join(q, []string{"ls", "a\u200Bb"}))
+}
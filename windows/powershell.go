@@ -7,58 +7,201 @@ import (
 	"unicode/utf8"
 
 	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
 )
 
 const psUnsafeChars = "\t \"$'`"
 
+var psUnsafe = quoteutil.NewASCIISet(psUnsafeChars)
+
 type psQuote struct{}
 
+// psSingleQuotes and psDoubleQuotes are non-ASCII characters
+// PowerShell treats as single and double quotes (‘ ’ ‚ ‛ and “ ” „).
+const (
+	psSingleQuotes = "\u2018\u2019\u201A\u201B"
+	psDoubleQuotes = "\u201C\u201D\u201E"
+)
+
+func isPSSingleQuote(r rune) bool {
+	return r == '\'' || r >= utf8.RuneSelf && strings.ContainsRune(psSingleQuotes, r)
+}
+
+func isPSDoubleQuote(r rune) bool {
+	return r == '"' || r >= utf8.RuneSelf && strings.ContainsRune(psDoubleQuotes, r)
+}
+
 func (psQuote) MustQuote(s string) bool {
-	return strings.ContainsAny(s, psUnsafeChars)
+	return psUnsafe.Index(s) >= 0 || strings.ContainsAny(s, psSingleQuotes+psDoubleQuotes)
 }
 
 type psSingleQuote struct {
 	psQuote
 }
 
-func (psSingleQuote) Quote(s string) string {
-	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+func (psSingleQuote) String() string { return "windows.PSSingleQuote" }
+
+func (q psSingleQuote) Quote(s string) string {
+	return string(q.AppendQuote(make([]byte, 0, len(s)+2), s))
+}
+
+func (q psSingleQuote) QuoteStrict(s string) (string, error) {
+	return quoteStrict(q, s, false)
+}
+
+func (q psSingleQuote) QuoteBinary(b []byte) string {
+	return q.Quote(string(b))
+}
+
+func (q psSingleQuote) QuoteBinaryStrict(b []byte) (string, error) {
+	return q.QuoteStrict(string(b))
+}
+
+func (q psSingleQuote) UnquoteBinary(s string) ([]byte, error) {
+	return q.AppendUnquote(nil, s)
+}
+
+func (psSingleQuote) AppendQuote(dst []byte, s string) []byte {
+	var q psSingleQuoter
+	dst, _, _ = q.Transform(dst, s, true)
+	return dst
 }
 
-func (psSingleQuote) Unquote(s string) (string, error) {
+func (psSingleQuote) NewQuoter() quote.Transformer {
+	return &psSingleQuoter{}
+}
+
+func (q psSingleQuote) Unquote(s string) (string, error) {
+	b, err := q.AppendUnquote(make([]byte, 0, len(s)), s)
+	return string(b), err
+}
+
+func (q psSingleQuote) AppendUnquote(dst []byte, s string) ([]byte, error) {
+	return quoteutil.AppendUnquote(dst, s, q)
+}
+
+func (psSingleQuote) NewUnquoter() quote.Transformer {
+	return &psSingleUnquoter{}
+}
+
+func (q psSingleQuote) Explain(s string) ([]quote.Span, error) {
+	u := psSingleUnquoter{spans: &quoteutil.SpanList{}}
+	b, _, err := u.unquote(nil, s, quoteutil.UnquoteAll, true)
+	return u.spans.Explain(b, err, q, s)
+}
+
+func (q psSingleQuote) Split(s string) ([]string, error) {
+	return quoteutil.Split(s, q, isBlank)
+}
+
+func (q psSingleQuote) QuotedPrefix(s string) (string, string, error) {
+	return quoteutil.QuotedPrefix(s, q)
+}
+
+func (psSingleQuote) UnquoteMode(dst []byte, s string, mode quoteutil.Mode) ([]byte, int, error) {
+	var u psSingleUnquoter
+	return u.unquote(dst, s, mode, true)
+}
+
+// psSingleEscaped are ASCII characters escaped by PSSingleQuote.
+var psSingleEscaped = quoteutil.NewASCIISet("'")
+
+type psSingleQuoter struct {
+	started bool
+}
+
+func (q *psSingleQuoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	if !q.started {
+		dst = append(dst, '\'')
+		q.started = true
+	}
+	for i, width := 0, 0; i < len(src); i += width {
+		// Runs of characters needing no escaping are copied as is.
+		if width = psSingleEscaped.Skip(src[i:]); width > 0 {
+			dst = append(dst, src[i:i+width]...)
+			continue
+		}
+		if !atEOF && !utf8.FullRuneInString(src[i:]) {
+			return dst, i, nil
+		}
+		var r rune
+		r, width = utf8.DecodeRuneInString(src[i:])
+		dst = append(dst, src[i:i+width]...)
+		if isPSSingleQuote(r) {
+			// Quotes are escaped by doubling them.
+			dst = append(dst, src[i:i+width]...)
+		}
+	}
+	if atEOF {
+		dst = append(dst, '\'')
+	}
+	return dst, len(src), nil
+}
+
+type psSingleUnquoter struct {
+	inQuote bool
+	spans   *quoteutil.SpanList
+}
+
+func (u *psSingleUnquoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	return u.unquote(dst, src, quoteutil.UnquoteAll, atEOF)
+}
+
+func (u *psSingleUnquoter) unquote(dst []byte, s string, mode quoteutil.Mode, atEOF bool) ([]byte, int, error) {
 	var (
-		buf     strings.Builder
-		inQuote bool
+		r        rune
+		i, width int
 	)
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\'' {
-			if !inQuote {
-				inQuote = true
+	for ; i < len(s); i += width {
+		if !atEOF && !utf8.FullRuneInString(s[i:]) {
+			break
+		}
+		r, width = utf8.DecodeRuneInString(s[i:])
+		n := len(dst)
+		if isPSSingleQuote(r) {
+			if !u.inQuote {
+				u.inQuote = true
+				u.spans.Add(quote.SpanOpenQuote, i, i+width, n, n)
+				continue
+			}
+			next := i + width
+			if !atEOF && !utf8.FullRuneInString(s[next:]) {
+				break
+			}
+			if r2, width2 := utf8.DecodeRuneInString(s[next:]); next < len(s) && isPSSingleQuote(r2) {
+				// A doubled quote stands for the second one.
+				dst = append(dst, s[next:next+width2]...)
+				u.spans.Add(quote.SpanEscape, i, next+width2, n, len(dst))
+				width += width2
 			} else {
-				if i+1 < len(s) && s[i+1] == '\'' {
-					buf.WriteByte('\'')
-					i++
-				} else {
-					inQuote = false
-				}
+				u.inQuote = false
+				u.spans.Add(quote.SpanCloseQuote, i, next, n, n)
 			}
 			continue
 		}
-		if !inQuote {
-			return "", &quote.SyntaxError{
-				Msg:    fmt.Sprintf("character %#U outside of quoted string", s[i]),
-				Offset: i + 1,
+		if !u.inQuote {
+			if mode == quoteutil.UnquoteAll || mode == quoteutil.UnquotePrefix && i == 0 {
+				return nil, 0, &quote.SyntaxError{
+					Msg:    fmt.Sprintf("character %#U outside of quoted string", r),
+					Kind:   quote.ErrOutsideQuotes,
+					Offset: i,
+				}
+			}
+			if mode == quoteutil.UnquotePrefix || isBlank(s[i]) {
+				break
 			}
 		}
-		buf.WriteByte(s[i])
+		dst = append(dst, s[i:i+width]...)
+		u.spans.Add(quote.SpanLiteral, i, i+width, n, len(dst))
 	}
-	if inQuote {
-		return "", &quote.SyntaxError{
+	if atEOF && u.inQuote {
+		return nil, 0, &quote.SyntaxError{
 			Msg:    "unterminated quoted string",
+			Kind:   quote.ErrUnterminated,
 			Offset: len(s),
 		}
 	}
-	return buf.String(), nil
+	return dst, i, nil
 }
 
 // PSSingleQuote quotes and unquotes strings, surrounded by single quotes ('…')
@@ -72,6 +215,12 @@ func (psSingleQuote) Unquote(s string) (string, error) {
 //
 //  'a b:"c d" ''e''''f''  "g\""'
 //
+// Curly single quotes (‘ ’ ‚ ‛), which PowerShell treats as single quotes, are doubled too.
+//
+// It implements quote.StrictBinaryQuoting:
+// QuoteBinary and UnquoteBinary treat bytes as UTF-8 encoded text,
+// so QuoteBinaryStrict rejects invalid UTF-8 sequences.
+//
 // See https://docs.microsoft.com/en-us/powershell/module/microsoft.powershell.core/about/about_quoting_rules?view=powershell-7.1
 // for details.
 var PSSingleQuote quote.Quoting = psSingleQuote{}
@@ -80,188 +229,323 @@ type basePSDoubleQuote struct {
 	psQuote
 }
 
-func (basePSDoubleQuote) Unquote(s string) (string, error) {
+func (basePSDoubleQuote) NewUnquoter() quote.Transformer {
+	return &psDoubleUnquoter{}
+}
+
+// explain returns the spans of s quoted with q.
+func (basePSDoubleQuote) explain(s string, q quoteutil.Dialect) ([]quote.Span, error) {
+	u := psDoubleUnquoter{spans: &quoteutil.SpanList{}}
+	b, _, err := u.unquote(nil, s, quoteutil.UnquoteAll, true)
+	return u.spans.Explain(b, err, q, s)
+}
+
+func (basePSDoubleQuote) UnquoteMode(dst []byte, s string, mode quoteutil.Mode) ([]byte, int, error) {
+	var u psDoubleUnquoter
+	return u.unquote(dst, s, mode, true)
+}
+
+// psMaxEscape is the maximum length of an escape sequence in bytes (`u{XXXXXX}).
+const psMaxEscape = 10
+
+type psDoubleUnquoter struct {
+	inQuote bool
+	spans   *quoteutil.SpanList
+}
+
+func (u *psDoubleUnquoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	return u.unquote(dst, src, quoteutil.UnquoteAll, atEOF)
+}
+
+func (u *psDoubleUnquoter) unquote(dst []byte, s string, mode quoteutil.Mode, atEOF bool) ([]byte, int, error) {
 	var (
-		r       rune
-		inQuote bool
-		buf     strings.Builder
-		b       = make([]rune, 6)
+		r        rune
+		i, width int
 	)
-	for i, width := 0, 0; i < len(s); i += width {
+	for ; i < len(s); i += width {
+		if !atEOF && !utf8.FullRuneInString(s[i:]) {
+			break
+		}
 		r, width = utf8.DecodeRuneInString(s[i:])
-		if r == '"' {
-			inQuote = !inQuote
+		n := len(dst)
+		if isPSDoubleQuote(r) {
+			u.inQuote = !u.inQuote
+			u.spans.AddQuote(u.inQuote, i, i+width, n)
 			continue
 		}
-		if !inQuote {
-			return "", &quote.SyntaxError{
-				Msg:    fmt.Sprintf("character %#U outside of quoted string", r),
-				Offset: i + 1,
+		if !u.inQuote {
+			if mode == quoteutil.UnquoteAll || mode == quoteutil.UnquotePrefix && i == 0 {
+				return nil, 0, &quote.SyntaxError{
+					Msg:    fmt.Sprintf("character %#U outside of quoted string", r),
+					Kind:   quote.ErrOutsideQuotes,
+					Offset: i,
+				}
 			}
+			if mode == quoteutil.UnquotePrefix || isBlank(s[i]) {
+				break
+			}
+			dst = append(dst, s[i:i+width]...)
+			u.spans.Add(quote.SpanLiteral, i, i+width, n, len(dst))
+			continue
 		}
 		if r != '`' {
 			switch r {
 			case '$':
-				return "", &quote.SyntaxError{
+				return nil, 0, &quote.SyntaxError{
 					Msg:    fmt.Sprintf("unescaped special character %#U", r),
-					Offset: i + 1,
+					Kind:   quote.ErrUnsupportedChar,
+					Offset: i,
 				}
 			default:
-				buf.WriteRune(r)
+				dst = append(dst, s[i:i+width]...)
+				u.spans.Add(quote.SpanLiteral, i, i+width, n, len(dst))
 			}
 			continue
 		}
+		if !atEOF && len(s)-i < psMaxEscape {
+			break
+		}
+		start := i
 		if i += width; i >= len(s) {
-			return "", &quote.SyntaxError{
+			return nil, 0, &quote.SyntaxError{
 				Msg:    "unterminated escape sequence",
-				Offset: len(s),
+				Kind:   quote.ErrBadEscape,
+				Offset: start,
 			}
 		}
 		r, width = utf8.DecodeRuneInString(s[i:])
 		switch r {
 		case '0':
-			buf.WriteRune('\000')
+			dst = append(dst, '\000')
 		case 'a':
-			buf.WriteRune('\a')
+			dst = append(dst, '\a')
 		case 'b':
-			buf.WriteRune('\b')
+			dst = append(dst, '\b')
 		case 'e':
-			buf.WriteRune('\x1B')
+			dst = append(dst, '\x1B')
 		case 'f':
-			buf.WriteRune('\f')
+			dst = append(dst, '\f')
 		case 'n':
-			buf.WriteRune('\n')
+			dst = append(dst, '\n')
 		case 'r':
-			buf.WriteRune('\r')
+			dst = append(dst, '\r')
 		case 't':
-			buf.WriteRune('\t')
+			dst = append(dst, '\t')
 		case 'v':
-			buf.WriteRune('\v')
+			dst = append(dst, '\v')
 		case '"', '$', '`':
-			buf.WriteRune(r)
+			dst = append(dst, byte(r))
 		case 'u':
 			if i += width; i >= len(s) {
-				return "", &quote.SyntaxError{
+				return nil, 0, &quote.SyntaxError{
 					Msg:    "unterminated escape sequence `u",
-					Offset: len(s),
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
 			r, width = utf8.DecodeRuneInString(s[i:])
 			if r != '{' {
-				return "", &quote.SyntaxError{
+				return nil, 0, &quote.SyntaxError{
 					Msg:    fmt.Sprintf("invalid character %#U in escape sequence '`u'", r),
-					Offset: i + 1,
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
-			b = b[:0]
-			for n := 6; n > 0 && i+width < len(s); n-- {
-				r1, width1 := utf8.DecodeRuneInString(s[i+width:])
-				if (r1 >= '0' && r1 <= '9') || (r1 >= 'a' && r1 <= 'f') || (r1 >= 'A' && r1 <= 'F') {
-					b = append(b, r1)
-					i += width
-					width = width1
-				} else {
-					break
-				}
+			j := i + 1
+			for ; j < len(s) && j-i-1 < 6 && quoteutil.IsHex(s[j]); j++ {
 			}
-			if len(b) == 0 {
-				return "", &quote.SyntaxError{
+			if j == i+1 {
+				return nil, 0, &quote.SyntaxError{
 					Msg:    "invalid escape sequence '`u'",
-					Offset: i + 1,
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
-			if i += width; i >= len(s) {
-				return "", &quote.SyntaxError{
+			digits := s[i+1 : j]
+			if i = j; i >= len(s) {
+				return nil, 0, &quote.SyntaxError{
 					Msg:    "unterminated escape sequence `u",
-					Offset: len(s),
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
 			r, width = utf8.DecodeRuneInString(s[i:])
 			if r != '}' {
-				return "", &quote.SyntaxError{
+				return nil, 0, &quote.SyntaxError{
 					Msg:    fmt.Sprintf("invalid character %#U in escape sequence '`u'", r),
-					Offset: i + 1,
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
-			v, err := strconv.ParseUint(string(b), 16, 48)
+			v, err := strconv.ParseUint(digits, 16, 48)
 			if err != nil || v > utf8.MaxRune {
-				return "", &quote.SyntaxError{
-					Msg:    fmt.Sprintf("invalid escape sequence '`u{%s}'", string(b)),
-					Offset: i + 1,
+				return nil, 0, &quote.SyntaxError{
+					Msg:    fmt.Sprintf("invalid escape sequence '`u{%s}'", digits),
+					Kind:   quote.ErrBadEscape,
+					Offset: start,
 				}
 			}
-			buf.WriteRune(rune(v))
+			dst = utf8.AppendRune(dst, rune(v))
 		default:
-			buf.WriteRune(r)
+			dst = append(dst, s[i:i+width]...)
 		}
+		u.spans.Add(quote.SpanEscape, start, i+width, n, len(dst))
 	}
-	if inQuote {
-		return "", &quote.SyntaxError{
+	if atEOF && u.inQuote {
+		return nil, 0, &quote.SyntaxError{
 			Msg:    "unterminated quoted string",
+			Kind:   quote.ErrUnterminated,
 			Offset: len(s),
 		}
 	}
-	return buf.String(), nil
+	return dst, i, nil
+}
+
+// psDoubleEscaped are ASCII characters escaped by PSDoubleQuote or PwshDoubleQuote.
+var psDoubleEscaped = func() *quoteutil.ASCIISet {
+	as := quoteutil.NewASCIISet("\"$`\x7F")
+	for c := byte(0); c < ' '; c++ {
+		as[c] = true
+	}
+	return as
+}()
+
+type psDoubleQuoter struct {
+	pwsh, started, displaySafe bool
 }
 
-func (basePSDoubleQuote) quote(s string, pwsh bool) string {
-	var buf strings.Builder
-	for _, r := range s {
+func (q *psDoubleQuoter) Transform(dst []byte, src string, atEOF bool) ([]byte, int, error) {
+	if !q.started {
+		dst = append(dst, '"')
+		q.started = true
+	}
+	for i, width := 0, 0; i < len(src); i += width {
+		// Runs of characters needing no escaping are copied as is.
+		if width = psDoubleEscaped.Skip(src[i:]); width > 0 {
+			dst = append(dst, src[i:i+width]...)
+			continue
+		}
+		if !atEOF && !utf8.FullRuneInString(src[i:]) {
+			return dst, i, nil
+		}
+		var r rune
+		r, width = utf8.DecodeRuneInString(src[i:])
+		if r == utf8.RuneError && width == 1 {
+			// PowerShell strings are UTF-16 so invalid UTF-8 bytes can't be escaped
+			// and are kept as is, QuoteStrict rejects them.
+			dst = append(dst, src[i])
+			continue
+		}
 		switch r {
 		case '\000':
-			buf.WriteString("`0")
+			dst = append(dst, "`0"...)
 		case '\a':
-			buf.WriteString("`a")
+			dst = append(dst, "`a"...)
 		case '\b':
-			buf.WriteString("`b")
+			dst = append(dst, "`b"...)
 		case '\x1B':
-			if pwsh {
-				buf.WriteString("`e")
+			if q.pwsh {
+				dst = append(dst, "`e"...)
 			} else {
-				buf.WriteRune(r)
+				dst = append(dst, byte(r))
 			}
 		case '\f':
-			buf.WriteString("`f")
+			dst = append(dst, "`f"...)
 		case '\n':
-			buf.WriteString("`n")
+			dst = append(dst, "`n"...)
 		case '\r':
-			buf.WriteString("`r")
+			dst = append(dst, "`r"...)
 		case '\t':
-			buf.WriteString("`t")
+			dst = append(dst, "`t"...)
 		case '\v':
-			buf.WriteString("`v")
+			dst = append(dst, "`v"...)
 		case '"', '$', '`':
-			buf.WriteRune('`')
-			buf.WriteRune(r)
+			dst = append(dst, '`', byte(r))
+		case '\u201C', '\u201D', '\u201E':
+			dst = append(dst, '`')
+			dst = utf8.AppendRune(dst, r)
 		default:
 			switch {
-			case pwsh && (r < 0x20 || !strconv.IsPrint(r)):
+			case q.pwsh && (r < 0x20 || !strconv.IsPrint(r) || q.displaySafe && quoteutil.IsHidden(r)):
 				switch {
 				case r < 0x7F:
-					buf.WriteString(fmt.Sprintf("`u{%02X}", r))
+					dst = quoteutil.AppendHex(dst, "`u{", r, 2)
 				case r < 0x10000:
-					buf.WriteString(fmt.Sprintf("`u{%04X}", r))
+					dst = quoteutil.AppendHex(dst, "`u{", r, 4)
 				default:
-					buf.WriteString(fmt.Sprintf("`u{%06X}", r))
+					dst = quoteutil.AppendHex(dst, "`u{", r, 6)
 				}
+				dst = append(dst, '}')
 			default:
-				buf.WriteRune(r)
+				dst = utf8.AppendRune(dst, r)
 			}
 		}
 	}
-	return `"` + buf.String() + `"`
+	if atEOF {
+		dst = append(dst, '"')
+	}
+	return dst, len(src), nil
 }
 
 type psDoubleQuote struct {
 	basePSDoubleQuote
 }
 
+func (psDoubleQuote) String() string { return "windows.PSDoubleQuote" }
+
 func (psDoubleQuote) MustQuote(s string) bool {
-	return strings.ContainsAny(s, psUnsafeChars)
+	return psQuote{}.MustQuote(s)
 }
 
 func (q psDoubleQuote) Quote(s string) string {
-	return q.quote(s, false)
+	return string(q.AppendQuote(make([]byte, 0, len(s)+2), s))
+}
+
+func (q psDoubleQuote) QuoteStrict(s string) (string, error) {
+	return quoteStrict(q, s, true)
+}
+
+func (q psDoubleQuote) QuoteBinary(b []byte) string {
+	return q.Quote(string(b))
+}
+
+func (q psDoubleQuote) QuoteBinaryStrict(b []byte) (string, error) {
+	return q.QuoteStrict(string(b))
+}
+
+func (q psDoubleQuote) UnquoteBinary(s string) ([]byte, error) {
+	return q.AppendUnquote(nil, s)
+}
+
+func (psDoubleQuote) AppendQuote(dst []byte, s string) []byte {
+	q := psDoubleQuoter{pwsh: false}
+	dst, _, _ = q.Transform(dst, s, true)
+	return dst
+}
+
+func (psDoubleQuote) NewQuoter() quote.Transformer {
+	return &psDoubleQuoter{pwsh: false}
+}
+
+func (q psDoubleQuote) Unquote(s string) (string, error) {
+	b, err := q.AppendUnquote(make([]byte, 0, len(s)), s)
+	return string(b), err
+}
+
+func (q psDoubleQuote) AppendUnquote(dst []byte, s string) ([]byte, error) {
+	return quoteutil.AppendUnquote(dst, s, q)
+}
+
+func (q psDoubleQuote) Explain(s string) ([]quote.Span, error) {
+	return q.explain(s, q)
+}
+
+func (q psDoubleQuote) Split(s string) ([]string, error) {
+	return quoteutil.Split(s, q, isBlank)
+}
+
+func (q psDoubleQuote) QuotedPrefix(s string) (string, string, error) {
+	return quoteutil.QuotedPrefix(s, q)
 }
 
 // PSDoubleQuote quotes and unquotes strings, surrounded by double quotes ("…")
@@ -275,6 +559,12 @@ func (q psDoubleQuote) Quote(s string) string {
 //
 //  "a b:`"c d`" 'e''f'  `"g\`"`""
 //
+// Curly double quotes (“ ” „), which PowerShell treats as double quotes, are escaped too.
+//
+// It implements quote.StrictBinaryQuoting:
+// QuoteBinary and UnquoteBinary treat bytes as UTF-8 encoded text,
+// so QuoteBinaryStrict rejects invalid UTF-8 sequences.
+//
 // See https://docs.microsoft.com/en-us/powershell/module/microsoft.powershell.core/about/about_quoting_rules?view=powershell-7.1
 // and https://docs.microsoft.com/en-us/powershell/module/microsoft.powershell.core/about/about_special_characters?view=powershell-7.1
 // for details.
@@ -284,12 +574,61 @@ type pwshDoubleQuote struct {
 	basePSDoubleQuote
 }
 
+func (pwshDoubleQuote) String() string { return "windows.PwshDoubleQuote" }
+
 func (pwshDoubleQuote) MustQuote(s string) bool {
-	return strings.ContainsAny(s, psUnsafeChars)
+	return psQuote{}.MustQuote(s)
 }
 
 func (q pwshDoubleQuote) Quote(s string) string {
-	return q.quote(s, true)
+	return string(q.AppendQuote(make([]byte, 0, len(s)+2), s))
+}
+
+func (q pwshDoubleQuote) QuoteStrict(s string) (string, error) {
+	return quoteStrict(q, s, true)
+}
+
+func (q pwshDoubleQuote) QuoteBinary(b []byte) string {
+	return q.Quote(string(b))
+}
+
+func (q pwshDoubleQuote) QuoteBinaryStrict(b []byte) (string, error) {
+	return q.QuoteStrict(string(b))
+}
+
+func (q pwshDoubleQuote) UnquoteBinary(s string) ([]byte, error) {
+	return q.AppendUnquote(nil, s)
+}
+
+func (pwshDoubleQuote) AppendQuote(dst []byte, s string) []byte {
+	q := psDoubleQuoter{pwsh: true}
+	dst, _, _ = q.Transform(dst, s, true)
+	return dst
+}
+
+func (pwshDoubleQuote) NewQuoter() quote.Transformer {
+	return &psDoubleQuoter{pwsh: true}
+}
+
+func (q pwshDoubleQuote) Unquote(s string) (string, error) {
+	b, err := q.AppendUnquote(make([]byte, 0, len(s)), s)
+	return string(b), err
+}
+
+func (q pwshDoubleQuote) AppendUnquote(dst []byte, s string) ([]byte, error) {
+	return quoteutil.AppendUnquote(dst, s, q)
+}
+
+func (q pwshDoubleQuote) Explain(s string) ([]quote.Span, error) {
+	return q.explain(s, q)
+}
+
+func (q pwshDoubleQuote) Split(s string) ([]string, error) {
+	return quoteutil.Split(s, q, isBlank)
+}
+
+func (q pwshDoubleQuote) QuotedPrefix(s string) (string, string, error) {
+	return quoteutil.QuotedPrefix(s, q)
 }
 
 // PwshDoubleQuote quotes and unquotes strings, surrounded by double quotes ("…")
@@ -303,6 +642,12 @@ func (q pwshDoubleQuote) Quote(s string) string {
 //
 //  "a b:`"c d`" 'e''f'  `"g\`"`""
 //
+// Curly double quotes (“ ” „), which PowerShell treats as double quotes, are escaped too.
+//
+// It implements quote.StrictBinaryQuoting:
+// QuoteBinary and UnquoteBinary treat bytes as UTF-8 encoded text,
+// so QuoteBinaryStrict rejects invalid UTF-8 sequences.
+//
 // See https://docs.microsoft.com/en-us/powershell/module/microsoft.powershell.core/about/about_quoting_rules?view=powershell-7.1
 // and https://docs.microsoft.com/en-us/powershell/module/microsoft.powershell.core/about/about_special_characters?view=powershell-7.1
 // for details.
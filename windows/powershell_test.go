@@ -1,6 +1,7 @@
 package windows
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -23,6 +24,11 @@ func TestPSSingleQuote_Quote(t *testing.T) {
 			Input:  "a'b",
 			Output: "'a''b'",
 		},
+		{
+			Name:   "curly quote escaping",
+			Input:  "a\u2018b\u2019\u201A\u201B",
+			Output: "'a\u2018\u2018b\u2019\u2019\u201A\u201A\u201B\u201B'",
+		},
 	}
 	for _, td := range tests {
 		t.Run(td.Name, func(t *testing.T) {
@@ -43,6 +49,11 @@ func TestPSSingleQuote_Unquote(t *testing.T) {
 		t.Fatalf("PSSingleQuote.Unquote() = _, %v; want nil", err)
 	}
 	testutil.TestDiff(t, "PSSingleQuote.Unquote()", "ab'''cd'ef", unquoted)
+	unquoted, err = PSSingleQuote.Unquote("‘a’’b'")
+	if err != nil {
+		t.Fatalf("PSSingleQuote.Unquote() = _, %v; want nil", err)
+	}
+	testutil.TestDiff(t, "PSSingleQuote.Unquote()", "a’b", unquoted)
 }
 
 func TestPSSingleQuote_Unquote_ShouldFail(t *testing.T) {
@@ -54,32 +65,44 @@ func TestPSSingleQuote_Unquote_ShouldFail(t *testing.T) {
 			Name:  "unterminated string #1",
 			Input: "'a",
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated quoted string",
-				Offset: 2,
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "windows.PSSingleQuote",
+				Input:   "'a",
+				Offset:  2,
 			},
 		},
 		{
 			Name:  "unterminated string #2",
 			Input: "'a''",
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated quoted string",
-				Offset: 4,
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "windows.PSSingleQuote",
+				Input:   "'a''",
+				Offset:  4,
 			},
 		},
 		{
 			Name:  "char outside of string",
 			Input: "a",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 1,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.PSSingleQuote",
+				Input:   "a",
+				Offset:  0,
 			},
 		},
 		{
 			Name:  "char after string",
 			Input: "'a'a",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 4,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.PSSingleQuote",
+				Input:   "'a'a",
+				Offset:  3,
 			},
 		},
 	}
@@ -126,6 +149,11 @@ func TestPSDoubleQuote_Quote_Unquote(t *testing.T) {
 			Input:  "\"$`",
 			Output: "\"`\"`$``\"",
 		},
+		{
+			Name:   "curly quote escaping",
+			Input:  "\u201Ca\u201D\u201E",
+			Output: "\"`\u201Ca`\u201D`\u201E\"",
+		},
 		{
 			Name:       "`u byte escaping",
 			Input:      "\x00\x01\x02\x03\x04\x05\x06\x0E\x0F\x10\x11\x12\x13\x14\x15\x16\x17\x18\x19\x1A\x1C\x1D\x1E\x1F",
@@ -144,6 +172,11 @@ func TestPSDoubleQuote_Quote_Unquote(t *testing.T) {
 			Output:     "\"\U0001000C\U00010027\U0001003B\U0001003E\U0001004E\"",
 			PwshOutput: "\"`u{01000C}`u{010027}`u{01003B}`u{01003E}`u{01004E}\"",
 		},
+		{
+			Name:   "invalid UTF-8",
+			Input:  "a\xFF\xD0\uFFFD",
+			Output: "\"a\xFF\xD0\uFFFD\"",
+		},
 	}
 	for _, td := range tests {
 		t.Run(td.Name, func(t *testing.T) {
@@ -195,6 +228,11 @@ func TestPSDoubleQuote_Unquote(t *testing.T) {
 			Input:  `"\p\z"`,
 			Output: `\p\z`,
 		},
+		{
+			Name:   "curly quotes",
+			Input:  "“a”\"b„",
+			Output: "ab",
+		},
 	}
 	for _, td := range tests {
 		t.Run(td.Name, func(t *testing.T) {
@@ -223,88 +261,121 @@ func TestPSDoubleQuote_Unquote_ShouldFail(t *testing.T) {
 			Name:  "unterminated string",
 			Input: `"a`,
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated quoted string",
-				Offset: 2,
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   `"a`,
+				Offset:  2,
 			},
 		},
 		{
 			Name:  "unterminated escape sequence",
 			Input: "\"`",
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated escape sequence",
-				Offset: 2,
+				Msg:     "unterminated escape sequence",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "\"`",
+				Offset:  1,
 			},
 		},
 		{
 			Name:  "char outside of string",
 			Input: "a",
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 1,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "a",
+				Offset:  0,
 			},
 		},
 		{
 			Name:  "char after string",
 			Input: `"a"a`,
 			Err: &quote.SyntaxError{
-				Msg:    "character U+0061 'a' outside of quoted string",
-				Offset: 4,
+				Msg:     "character U+0061 'a' outside of quoted string",
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   `"a"a`,
+				Offset:  3,
 			},
 		},
 		{
 			Name:  `unescaped $`,
 			Input: `"$"`,
 			Err: &quote.SyntaxError{
-				Msg:    "unescaped special character U+0024 '$'",
-				Offset: 2,
+				Msg:     "unescaped special character U+0024 '$'",
+				Kind:    quote.ErrUnsupportedChar,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   `"$"`,
+				Offset:  1,
 			},
 		},
 		{
 			Name:  "unterminated `u #1",
 			Input: "\"`u",
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated escape sequence `u",
-				Offset: 3,
+				Msg:     "unterminated escape sequence `u",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "\"`u",
+				Offset:  1,
 			},
 		},
 		{
 			Name:  "unterminated `u #2",
 			Input: "\"`u{",
 			Err: &quote.SyntaxError{
-				Msg:    "invalid escape sequence '`u'",
-				Offset: 4,
+				Msg:     "invalid escape sequence '`u'",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "\"`u{",
+				Offset:  1,
 			},
 		},
 		{
 			Name:  "unterminated `u #3",
 			Input: "\"`u{f",
 			Err: &quote.SyntaxError{
-				Msg:    "unterminated escape sequence `u",
-				Offset: 5,
+				Msg:     "unterminated escape sequence `u",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "\"`u{f",
+				Offset:  1,
 			},
 		},
 		{
 			Name:  "invalid `u #1",
 			Input: "\"`u{ffffff}\"",
 			Err: &quote.SyntaxError{
-				Msg:    "invalid escape sequence '`u{ffffff}'",
-				Offset: 11,
+				Msg:     "invalid escape sequence '`u{ffffff}'",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "\"`u{ffffff}\"",
+				Offset:  1,
 			},
 		},
 		{
 			Name:  "invalid `u #2",
 			Input: "\"`u[ffffff}\"",
 			Err: &quote.SyntaxError{
-				Msg:    "invalid character U+005B '[' in escape sequence '`u'",
-				Offset: 4,
+				Msg:     "invalid character U+005B '[' in escape sequence '`u'",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "\"`u[ffffff}\"",
+				Offset:  1,
 			},
 		},
 		{
 			Name:  "invalid `u #3",
 			Input: "\"`u{ffffff]\"",
 			Err: &quote.SyntaxError{
-				Msg:    "invalid character U+005D ']' in escape sequence '`u'",
-				Offset: 11,
+				Msg:     "invalid character U+005D ']' in escape sequence '`u'",
+				Kind:    quote.ErrBadEscape,
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "\"`u{ffffff]\"",
+				Offset:  1,
 			},
 		},
 	}
@@ -317,7 +388,9 @@ func TestPSDoubleQuote_Unquote_ShouldFail(t *testing.T) {
 		})
 		t.Run(td.Name+";pwsh.exe", func(t *testing.T) {
 			_, err := PwshDoubleQuote.Unquote(td.Input)
-			if diff := cmp.Diff(td.Err, err); diff != "" {
+			expected := *td.Err.(*quote.SyntaxError)
+			expected.Dialect = "windows.PwshDoubleQuote"
+			if diff := cmp.Diff(&expected, err); diff != "" {
 				t.Errorf("PwshDoubleQuote.Unquote() mismatch (-want +got):\n%s", diff)
 			}
 		})
@@ -355,3 +428,36 @@ func TestPwshDoubleQuote_Quote_Unquote_InputTests(t *testing.T) {
 		})
 	}
 }
+
+func TestPSSingleQuote_Split(t *testing.T) {
+	args, err := PSSingleQuote.(quote.Splitter).Split(`Write-Output 'it''s' ''`)
+	if err != nil {
+		t.Fatalf("PSSingleQuote.Split() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff([]string{"Write-Output", "it's", ""}, args); diff != "" {
+		t.Errorf("PSSingleQuote.Split() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPSDoubleQuote_Split(t *testing.T) {
+	for _, q := range []quote.Quoting{PSDoubleQuote, PwshDoubleQuote} {
+		args, err := q.(quote.Splitter).Split("Write-Output \"a`tb\" x")
+		if err != nil {
+			t.Fatalf("Split() = _, %v; want nil", err)
+		}
+		if diff := cmp.Diff([]string{"Write-Output", "a\tb", "x"}, args); diff != "" {
+			t.Errorf("Split() mismatch (-want +got):\n%s", diff)
+		}
+		_, err = q.(quote.Splitter).Split(`a "$"`)
+		expected := &quote.SyntaxError{
+			Msg:     "unescaped special character U+0024 '$'",
+			Kind:    quote.ErrUnsupportedChar,
+			Dialect: q.(fmt.Stringer).String(),
+			Input:   `a "$"`,
+			Offset:  3,
+		}
+		if diff := cmp.Diff(expected, err); diff != "" {
+			t.Errorf("Split() mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
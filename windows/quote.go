@@ -0,0 +1,41 @@
+package windows
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/quoteutil"
+)
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// quoteStrict quotes s with q unless it contains invalid UTF-8 sequences,
+// which can't be converted to UTF-16, or, if allowNUL is false, NUL bytes.
+func quoteStrict(q interface {
+	quote.Quoting
+	String() string
+}, s string, allowNUL bool) (string, error) {
+	if err := checkStrict(q.String(), s, allowNUL); err != nil {
+		return "", err
+	}
+	return q.Quote(s), nil
+}
+
+// checkStrict returns an error if s contains invalid UTF-8 sequences or, if allowNUL is false, NUL bytes,
+// that the quoting named name can't represent.
+func checkStrict(name, s string, allowNUL bool) error {
+	for i := 0; i < len(s); {
+		r, width := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == 0 && !allowNUL:
+			return quoteutil.Unrepresentable(name, s, i, fmt.Sprintf("unsupported character %#U", r))
+		case r == utf8.RuneError && width == 1:
+			return quoteutil.Unrepresentable(name, s, i, fmt.Sprintf("invalid UTF-8 byte %#02x", s[i]))
+		}
+		i += width
+	}
+	return nil
+}
@@ -0,0 +1,402 @@
+package windows
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergeymakinen/go-quote"
+	"github.com/sergeymakinen/go-quote/internal/testutil"
+)
+
+func TestQuotedPrefix(t *testing.T) {
+	tests := []struct {
+		Name               string
+		Q                  quote.Quoting
+		Input, Token, Rest string
+		Err                error
+	}{
+		{
+			Name:  "Argv",
+			Q:     Argv,
+			Input: `"a \"b\"\\" c`,
+			Token: `a "b"\`,
+			Rest:  " c",
+		},
+		{
+			Name:  "Argv;backslash after string",
+			Q:     Argv,
+			Input: `"a"\b`,
+			Token: "a",
+			Rest:  `\b`,
+		},
+		{
+			Name:  "Cmd",
+			Q:     Cmd,
+			Input: `a^ ^&b c`,
+			Token: "a &b",
+			Rest:  " c",
+		},
+		{
+			Name:  "Msiexec",
+			Q:     Msiexec,
+			Input: `"a ""b""" -> c`,
+			Token: `a "b"`,
+			Rest:  " -> c",
+		},
+		{
+			Name:  "PSSingleQuote",
+			Q:     PSSingleQuote,
+			Input: `'it''s'x`,
+			Token: "it's",
+			Rest:  "x",
+		},
+		{
+			Name:  "PSDoubleQuote",
+			Q:     PSDoubleQuote,
+			Input: "\"a`tb\" c",
+			Token: "a\tb",
+			Rest:  " c",
+		},
+		{
+			Name:  "PwshDoubleQuote",
+			Q:     PwshDoubleQuote,
+			Input: "\"`u{1F600}\"",
+			Token: "\U0001F600",
+			Rest:  "",
+		},
+		{
+			Name:  "Argv;unquoted string",
+			Q:     Argv,
+			Input: `\"a"`,
+			Err: &quote.SyntaxError{
+				Msg:     `character U+005C '\' outside of quoted string`,
+				Kind:    quote.ErrOutsideQuotes,
+				Dialect: "windows.Argv",
+				Input:   `\"a"`,
+				Offset:  0,
+			},
+		},
+		{
+			Name:  "Msiexec;unterminated string",
+			Q:     Msiexec,
+			Input: `"a""`,
+			Err: &quote.SyntaxError{
+				Msg:     "unterminated quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "windows.Msiexec",
+				Input:   `"a""`,
+				Offset:  4,
+			},
+		},
+		{
+			Name:  "Cmd;empty string",
+			Q:     Cmd,
+			Input: "",
+			Err: &quote.SyntaxError{
+				Msg:     "missing quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "windows.Cmd",
+				Offset:  0,
+			},
+		},
+		{
+			Name:  "PSSingleQuote;empty string",
+			Q:     PSSingleQuote,
+			Input: "",
+			Err: &quote.SyntaxError{
+				Msg:     "missing quoted string",
+				Kind:    quote.ErrUnterminated,
+				Dialect: "windows.PSSingleQuote",
+				Input:   "",
+				Offset:  0,
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			token, rest, err := td.Q.(quote.Prefixer).QuotedPrefix(td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuotedPrefix() mismatch (-want +got):\n%s", diff)
+			}
+			testutil.TestDiff(t, "QuotedPrefix() token", td.Token, token)
+			testutil.TestDiff(t, "QuotedPrefix() rest", td.Rest, rest)
+		})
+	}
+}
+
+func TestQuoteStrict(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Q             quote.Quoting
+		Input, Output string
+		Err           error
+	}{
+		{
+			Name:   "Argv",
+			Q:      Argv,
+			Input:  "a\nb",
+			Output: "\"a\nb\"",
+		},
+		{
+			Name:  "Argv;NUL",
+			Q:     Argv,
+			Input: "a\x00",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "windows.Argv",
+				Input:   "a\x00",
+				Offset:  1,
+			},
+		},
+		{
+			Name:   "Cmd",
+			Q:      Cmd,
+			Input:  "100% & more",
+			Output: "100%^ ^&^ more",
+		},
+		{
+			Name:  "Cmd;newline",
+			Q:     Cmd,
+			Input: "a\r\nb",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+000D",
+				Dialect: "windows.Cmd",
+				Input:   "a\r\nb",
+				Offset:  1,
+			},
+		},
+		{
+			Name:  "Cmd;variable",
+			Q:     Cmd,
+			Input: "echo %PATH%",
+			Err: &quote.UnrepresentableError{
+				Msg:     "variable reference %PATH%",
+				Dialect: "windows.Cmd",
+				Input:   "echo %PATH%",
+				Offset:  5,
+			},
+		},
+		{
+			Name:  "Msiexec;invalid UTF-8",
+			Q:     Msiexec,
+			Input: "a\xFF",
+			Err: &quote.UnrepresentableError{
+				Msg:     "invalid UTF-8 byte 0xff",
+				Dialect: "windows.Msiexec",
+				Input:   "a\xFF",
+				Offset:  1,
+			},
+		},
+		{
+			Name:  "PSSingleQuote;NUL",
+			Q:     PSSingleQuote,
+			Input: "\x00",
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "windows.PSSingleQuote",
+				Input:   "\x00",
+				Offset:  0,
+			},
+		},
+		{
+			Name:   "PSDoubleQuote",
+			Q:      PSDoubleQuote,
+			Input:  "a\x00",
+			Output: "\"a`0\"",
+		},
+		{
+			Name:  "PwshDoubleQuote;invalid UTF-8",
+			Q:     PwshDoubleQuote,
+			Input: "\xC0\x80",
+			Err: &quote.UnrepresentableError{
+				Msg:     "invalid UTF-8 byte 0xc0",
+				Dialect: "windows.PwshDoubleQuote",
+				Input:   "\xC0\x80",
+				Offset:  0,
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			quoted, err := td.Q.(quote.StrictQuoting).QuoteStrict(td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuoteStrict() mismatch (-want +got):\n%s", diff)
+			}
+			testutil.TestDiff(t, "QuoteStrict()", td.Output, quoted)
+		})
+	}
+}
+
+func TestQuoteBinaryStrict_UnquoteBinary(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Q      quote.Quoting
+		Input  []byte
+		Output string
+		Err    error
+	}{
+		{
+			Name:   "PSSingleQuote",
+			Q:      PSSingleQuote,
+			Input:  []byte("a'ж"),
+			Output: "'a''ж'",
+		},
+		{
+			Name:  "PSSingleQuote;NUL",
+			Q:     PSSingleQuote,
+			Input: []byte("a\x00"),
+			Err: &quote.UnrepresentableError{
+				Msg:     "unsupported character U+0000",
+				Dialect: "windows.PSSingleQuote",
+				Input:   "a\x00",
+				Offset:  1,
+			},
+		},
+		{
+			Name:   "PSDoubleQuote",
+			Q:      PSDoubleQuote,
+			Input:  []byte("$\x00"),
+			Output: "\"`$`0\"",
+		},
+		{
+			Name:  "PSDoubleQuote;invalid UTF-8",
+			Q:     PSDoubleQuote,
+			Input: []byte("a\xFF"),
+			Err: &quote.UnrepresentableError{
+				Msg:     "invalid UTF-8 byte 0xff",
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "a\xFF",
+				Offset:  1,
+			},
+		},
+		{
+			Name:  "PwshDoubleQuote;invalid UTF-8",
+			Q:     PwshDoubleQuote,
+			Input: []byte("\xC0\x80"),
+			Err: &quote.UnrepresentableError{
+				Msg:     "invalid UTF-8 byte 0xc0",
+				Dialect: "windows.PwshDoubleQuote",
+				Input:   "\xC0\x80",
+				Offset:  0,
+			},
+		},
+		{
+			Name:  "NewPSDoubleQuote;display-safe",
+			Q:     NewPSDoubleQuote(DisplaySafe()),
+			Input: []byte("a\u200Bb"),
+			Err: &quote.UnrepresentableError{
+				Msg:     "invisible character U+200B",
+				Dialect: "windows.PSDoubleQuote",
+				Input:   "a\u200Bb",
+				Offset:  1,
+			},
+		},
+		{
+			Name:   "NewPwshDoubleQuote;display-safe",
+			Q:      NewPwshDoubleQuote(DisplaySafe()),
+			Input:  []byte("a\u200Bb"),
+			Output: "\"a`u{200B}b\"",
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			q := td.Q.(quote.StrictBinaryQuoting)
+			quoted, err := q.QuoteBinaryStrict(td.Input)
+			if diff := cmp.Diff(td.Err, err); diff != "" {
+				t.Fatalf("QuoteBinaryStrict() mismatch (-want +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			testutil.TestDiff(t, "QuoteBinaryStrict()", td.Output, quoted)
+			unquoted, err := q.UnquoteBinary(quoted)
+			if err != nil {
+				t.Fatalf("UnquoteBinary() = _, %v; want nil", err)
+			}
+			if diff := cmp.Diff(td.Input, unquoted); diff != "" {
+				t.Errorf("UnquoteBinary() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Q     quote.Quoting
+		Input string
+		Spans []quote.Span
+	}{
+		{
+			Name:  "Argv",
+			Q:     Argv,
+			Input: `"a\\\"b\\"`,
+			Spans: []quote.Span{
+				{Start: 0, End: 1, Kind: quote.SpanOpenQuote},
+				{Start: 1, End: 2, Kind: quote.SpanLiteral, Value: "a"},
+				{Start: 2, End: 6, Kind: quote.SpanEscape, Value: `\"`},
+				{Start: 6, End: 7, Kind: quote.SpanLiteral, Value: "b"},
+				{Start: 7, End: 9, Kind: quote.SpanEscape, Value: `\`},
+				{Start: 9, End: 10, Kind: quote.SpanCloseQuote},
+			},
+		},
+		{
+			Name:  "Cmd",
+			Q:     Cmd,
+			Input: `a^&b^c`,
+			Spans: []quote.Span{
+				{Start: 0, End: 1, Kind: quote.SpanLiteral, Value: "a"},
+				{Start: 1, End: 3, Kind: quote.SpanEscape, Value: "&"},
+				{Start: 3, End: 6, Kind: quote.SpanLiteral, Value: "b^c"},
+			},
+		},
+		{
+			Name:  "Msiexec",
+			Q:     Msiexec,
+			Input: `"a""b"`,
+			Spans: []quote.Span{
+				{Start: 0, End: 1, Kind: quote.SpanOpenQuote},
+				{Start: 1, End: 2, Kind: quote.SpanLiteral, Value: "a"},
+				{Start: 2, End: 4, Kind: quote.SpanEscape, Value: `"`},
+				{Start: 4, End: 5, Kind: quote.SpanLiteral, Value: "b"},
+				{Start: 5, End: 6, Kind: quote.SpanCloseQuote},
+			},
+		},
+		{
+			Name:  "PSSingleQuote",
+			Q:     PSSingleQuote,
+			Input: `'it''s'`,
+			Spans: []quote.Span{
+				{Start: 0, End: 1, Kind: quote.SpanOpenQuote},
+				{Start: 1, End: 3, Kind: quote.SpanLiteral, Value: "it"},
+				{Start: 3, End: 5, Kind: quote.SpanEscape, Value: "'"},
+				{Start: 5, End: 6, Kind: quote.SpanLiteral, Value: "s"},
+				{Start: 6, End: 7, Kind: quote.SpanCloseQuote},
+			},
+		},
+		{
+			Name:  "PwshDoubleQuote",
+			Q:     PwshDoubleQuote,
+			Input: "\"a`$`u{263A}`n\"",
+			Spans: []quote.Span{
+				{Start: 0, End: 1, Kind: quote.SpanOpenQuote},
+				{Start: 1, End: 2, Kind: quote.SpanLiteral, Value: "a"},
+				{Start: 2, End: 4, Kind: quote.SpanEscape, Value: "$"},
+				{Start: 4, End: 12, Kind: quote.SpanEscape, Value: "☺"},
+				{Start: 12, End: 14, Kind: quote.SpanEscape, Value: "\n"},
+				{Start: 14, End: 15, Kind: quote.SpanCloseQuote},
+			},
+		},
+	}
+	for _, td := range tests {
+		t.Run(td.Name, func(t *testing.T) {
+			spans, err := td.Q.(quote.Explainer).Explain(td.Input)
+			if err != nil {
+				t.Fatalf("Explain() = _, %v; want nil", err)
+			}
+			if diff := cmp.Diff(td.Spans, spans); diff != "" {
+				t.Errorf("Explain() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
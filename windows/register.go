@@ -0,0 +1,26 @@
+package windows
+
+import "github.com/sergeymakinen/go-quote"
+
+func init() {
+	for _, d := range []quote.Dialect{
+		{Name: "windows.Argv", Quoting: Argv, Newline: true},
+		{Name: "windows.Cmd", Quoting: Cmd},
+		{Name: "windows.Msiexec", Quoting: Msiexec, Newline: true},
+		{Name: "windows.PSSingleQuote", Quoting: PSSingleQuote, Newline: true, Binary: true},
+		{Name: "windows.PSDoubleQuote", Quoting: PSDoubleQuote, NUL: true, Newline: true, Binary: true},
+		{Name: "windows.PwshDoubleQuote", Quoting: PwshDoubleQuote, NUL: true, Newline: true, Binary: true},
+	} {
+		quote.Register(d)
+	}
+	for alias, name := range map[string]string{
+		"cmd":        "windows.Cmd",
+		"msiexec":    "windows.Msiexec",
+		"powershell": "windows.PSDoubleQuote",
+		"pwsh":       "windows.PwshDoubleQuote",
+	} {
+		d, _ := quote.Lookup(name)
+		d.Name = alias
+		quote.Register(d)
+	}
+}